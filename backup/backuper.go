@@ -0,0 +1,198 @@
+package backup
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/deminds/CmdProxy/inventory"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/session"
+	"github.com/deminds/CmdProxy/session/types"
+	"github.com/golang/glog"
+)
+
+func NewBackuper(
+	deviceInventory *inventory.Inventory,
+	telnetFactory *types.TelnetSessionFactory,
+	store *Store,
+	concurrency int) *Backuper {
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &Backuper{
+		inventory:     deviceInventory,
+		telnetFactory: telnetFactory,
+		store:         store,
+		concurrency:   concurrency,
+	}
+}
+
+// Backup configs of inventory devices by backup command of device profile
+type Backuper struct {
+	inventory     *inventory.Inventory
+	telnetFactory *types.TelnetSessionFactory
+	store         *Store
+	concurrency   int
+}
+
+func (o *Backuper) GetStore() *Store {
+	return o.store
+}
+
+// Devices selected by names, tags and groups. All devices if request is empty
+func (o *Backuper) Select(request model.BackupRequest) ([]model.Device, error) {
+	if len(request.Devices) == 0 && len(request.Tags) == 0 && len(request.Groups) == 0 {
+		return o.inventory.List(nil, nil), nil
+	}
+
+	selected := map[string]bool{}
+	res := []model.Device{}
+
+	for _, name := range request.Devices {
+		device, err := o.inventory.Get(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !selected[name] {
+			selected[name] = true
+			res = append(res, device)
+		}
+	}
+
+	if len(request.Tags) > 0 || len(request.Groups) > 0 {
+		for _, device := range o.inventory.List(request.Tags, request.Groups) {
+			if !selected[device.Name] {
+				selected[device.Name] = true
+				res = append(res, device)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// Backup devices with bounded concurrency. Error of one device does not stop others
+func (o *Backuper) Run(devices []model.Device) []model.BackupResult {
+	results := make([]model.BackupResult, len(devices))
+
+	semaphore := make(chan struct{}, o.concurrency)
+	wg := sync.WaitGroup{}
+
+	for idx, device := range devices {
+		semaphore <- struct{}{}
+		wg.Add(1)
+
+		go func(idx int, device model.Device) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			results[idx] = o.backupDevice(device)
+		}(idx, device)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// Backup devices selected by request every interval
+func (o *Backuper) Start(interval time.Duration, request model.BackupRequest) {
+	glog.Infof("Backuper.Start() Interval: %v, Request: %+v", interval, request)
+
+	go func() {
+		for range time.Tick(interval) {
+			devices, err := o.Select(request)
+			if err != nil {
+				glog.Errorf("Backuper.Start() Select devices. Error: %v", err)
+
+				continue
+			}
+
+			for _, res := range o.Run(devices) {
+				if res.Status != model.Ok {
+					glog.Errorf("Backuper.Start() Backup failed. Device: %v, Error: %v", res.Device, res.Error)
+				}
+			}
+		}
+	}()
+}
+
+func (o *Backuper) backupDevice(device model.Device) model.BackupResult {
+	logPrefix := "Backuper.backupDevice()"
+
+	res := model.BackupResult{
+		Device: device.Name,
+		Status: model.Ok,
+	}
+
+	config, err := o.showConfig(device)
+	if err == nil {
+		res.Version, res.Changed, err = o.store.Save(device.Name, config)
+	}
+
+	if err != nil {
+		glog.Errorf("%v Device: %v, Error: %v", logPrefix, device.Name, err)
+		res.Status = model.Error
+		res.Error = err.Error()
+
+		return res
+	}
+
+	glog.Infof("%v Device: %v, Version: %v, Changed: %v", logPrefix, device.Name, res.Version, res.Changed)
+
+	return res
+}
+
+func (o *Backuper) showConfig(device model.Device) (string, error) {
+	logPrefix := "Backuper.showConfig()"
+
+	profile, err := o.inventory.GetProfile(device.Profile)
+	if err != nil {
+		return "", err
+	}
+
+	if profile.BackupCommand == "" {
+		return "", fmt.Errorf("%v Profile has no backup command. Device: %v, Profile: %v", logPrefix, device.Name, device.Profile)
+	}
+
+	requestData, err := o.inventory.Resolve(model.ConnectTelnetRequest{Device: device.Name})
+	if err != nil {
+		return "", err
+	}
+	if !requestData.IsValid() {
+		return "", fmt.Errorf("device settings are not valid. Device: %v", device.Name)
+	}
+
+	sess, err := o.telnetFactory.New(requestData)
+	if err != nil {
+		return "", err
+	}
+
+	if err := sess.Connect(); err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if !sess.IsClose() {
+			sess.Close()
+		}
+	}()
+
+	cmdResult, err := sess.Command(profile.BackupCommand, session.CommandOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	// cut config is not a valid version
+	if cmdResult.Truncated {
+		return "", fmt.Errorf("%v Config is cut by read limit. Device: %v, Size: %v", logPrefix, device.Name, len(cmdResult.Output))
+	}
+
+	return cmdResult.Output, nil
+}
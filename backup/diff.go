@@ -0,0 +1,206 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	diffContext = 3
+	// edit distance limit of Myers algorithm, bigger changes are shown as full replace
+	maxEditDistance = 2000
+)
+
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// Unified diff of two texts. Empty string if texts are equal
+func UnifiedDiff(fromName, toName, from, to string) string {
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	// position in from and to before each op
+	fromPos := make([]int, len(ops)+1)
+	toPos := make([]int, len(ops)+1)
+	for idx, op := range ops {
+		fromPos[idx+1], toPos[idx+1] = fromPos[idx], toPos[idx]
+		if op.kind != '+' {
+			fromPos[idx+1]++
+		}
+		if op.kind != '-' {
+			toPos[idx+1]++
+		}
+	}
+
+	buf := strings.Builder{}
+	for idx := 0; idx < len(ops); {
+		if ops[idx].kind == ' ' {
+			idx++
+
+			continue
+		}
+
+		if buf.Len() == 0 {
+			fmt.Fprintf(&buf, "--- %v\n+++ %v\n", fromName, toName)
+		}
+
+		start := idx - diffContext
+		if start < 0 {
+			start = 0
+		}
+
+		// hunk ends when equal lines are more than both contexts
+		end := idx
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+
+				continue
+			}
+
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+
+			if run == len(ops) || run-end > 2*diffContext {
+				end += diffContext
+				if end > len(ops) {
+					end = len(ops)
+				}
+
+				break
+			}
+			end = run
+		}
+
+		fmt.Fprintf(&buf, "@@ -%v +%v @@\n",
+			hunkRange(fromPos[start], fromPos[end]-fromPos[start]),
+			hunkRange(toPos[start], toPos[end]-toPos[start]))
+
+		for _, op := range ops[start:end] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			buf.WriteByte('\n')
+		}
+
+		idx = end
+	}
+
+	return buf.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%v,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%v", start+1)
+	}
+
+	return fmt.Sprintf("%v,%v", start+1, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Myers diff. Return edit script: ' ' equal line, '-' removed line, '+' added line
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	maxD := n + m
+	if maxD > maxEditDistance {
+		maxD = maxEditDistance
+	}
+
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	trace := [][]int{}
+
+	found := false
+	for d := 0; d <= maxD && !found; d++ {
+		trace = append(trace, append([]int{}, v...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				found = true
+
+				break
+			}
+		}
+	}
+
+	if !found {
+		return replaceAll(a, b)
+	}
+
+	ops := []diffOp{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{kind: ' ', line: a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: '+', line: b[y-1]})
+				y--
+			} else {
+				ops = append(ops, diffOp{kind: '-', line: a[x-1]})
+				x--
+			}
+		}
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+func replaceAll(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a {
+		ops = append(ops, diffOp{kind: '-', line: line})
+	}
+	for _, line := range b {
+		ops = append(ops, diffOp{kind: '+', line: line})
+	}
+
+	return ops
+}
@@ -0,0 +1,171 @@
+package backup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+const (
+	// version is a timestamp of backup, sortable as string
+	VersionLayout = "20060102T150405.000000000Z"
+	ConfigFileExt = ".cfg"
+)
+
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("NewStore() Create dir. Dir: %v, Error: %v", dir, err)
+	}
+
+	return &Store{
+		dir:   dir,
+		mutex: sync.Mutex{},
+	}, nil
+}
+
+// Keep configs as timestamped files: <dir>/<device>/<version>.cfg
+type Store struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// Save config as new version if it differs from the last one.
+// Return version of config and true if new version was created
+func (o *Store) Save(device string, config string) (string, bool, error) {
+	logPrefix := "Store.Save()"
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	deviceDir, err := o.deviceDir(device)
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := os.MkdirAll(deviceDir, 0700); err != nil {
+		return "", false, fmt.Errorf("%v Create dir. Device: %v, Error: %v", logPrefix, device, err)
+	}
+
+	versions, err := o.versions(deviceDir)
+	if err != nil {
+		return "", false, err
+	}
+
+	if len(versions) > 0 {
+		last := versions[len(versions)-1]
+
+		lastConfig, err := ioutil.ReadFile(filepath.Join(deviceDir, last.Version+ConfigFileExt))
+		if err != nil {
+			return "", false, fmt.Errorf("%v Read last version. Device: %v, Version: %v, Error: %v", logPrefix, device, last.Version, err)
+		}
+
+		if string(lastConfig) == config {
+			return last.Version, false, nil
+		}
+	}
+
+	version := time.Now().UTC().Format(VersionLayout)
+	path := filepath.Join(deviceDir, version+ConfigFileExt)
+
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		return "", false, fmt.Errorf("%v Write file. Device: %v, Path: %v, Error: %v", logPrefix, device, path, err)
+	}
+
+	return version, true, nil
+}
+
+// Versions of device config sorted from old to new
+func (o *Store) List(device string) ([]model.BackupVersion, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	deviceDir, err := o.deviceDir(device)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.versions(deviceDir)
+}
+
+func (o *Store) Get(device string, version string) (string, error) {
+	logPrefix := "Store.Get()"
+
+	deviceDir, err := o.deviceDir(device)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := time.Parse(VersionLayout, version); err != nil {
+		return "", fmt.Errorf("%v Wrong version. Device: %v, Version: %v", logPrefix, device, version)
+	}
+
+	config, err := ioutil.ReadFile(filepath.Join(deviceDir, version+ConfigFileExt))
+	if err != nil {
+		return "", fmt.Errorf("%v Read file. Device: %v, Version: %v, Error: %v", logPrefix, device, version, err)
+	}
+
+	return string(config), nil
+}
+
+// Unified diff between two versions of device config
+func (o *Store) Diff(device string, fromVersion string, toVersion string) (string, error) {
+	from, err := o.Get(device, fromVersion)
+	if err != nil {
+		return "", err
+	}
+
+	to, err := o.Get(device, toVersion)
+	if err != nil {
+		return "", err
+	}
+
+	return UnifiedDiff(device+"/"+fromVersion, device+"/"+toVersion, from, to), nil
+}
+
+func (o *Store) versions(deviceDir string) ([]model.BackupVersion, error) {
+	logPrefix := "Store.versions()"
+
+	files, err := ioutil.ReadDir(deviceDir)
+	if os.IsNotExist(err) {
+		return []model.BackupVersion{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v Read dir. Dir: %v, Error: %v", logPrefix, deviceDir, err)
+	}
+
+	res := []model.BackupVersion{}
+	for _, file := range files {
+		version := strings.TrimSuffix(file.Name(), ConfigFileExt)
+
+		created, err := time.Parse(VersionLayout, version)
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ConfigFileExt) || err != nil {
+			continue
+		}
+
+		res = append(res, model.BackupVersion{
+			Version: version,
+			Time:    created,
+			Size:    file.Size(),
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res, nil
+}
+
+// Device name is used as directory name, it should not escape store dir
+func (o *Store) deviceDir(device string) (string, error) {
+	if device == "" || device == "." || device == ".." || strings.ContainsAny(device, `/\`) {
+		return "", fmt.Errorf("Store.deviceDir() Wrong device name: '%v'", device)
+	}
+
+	return filepath.Join(o.dir, device), nil
+}
@@ -0,0 +1,86 @@
+package charset
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Charsets are IANA names or aliases, e.g. "ISO-8859-1", "latin1", "GBK". Empty is utf-8
+
+// Check that output of charset can be converted to utf-8
+func IsSupported(name string) bool {
+	_, err := lookup(name)
+
+	return err == nil
+}
+
+// Output of charset is utf-8 as is. Unsupported charset is handled as utf-8
+func IsUtf8(name string) bool {
+	enc, err := lookup(name)
+
+	return err != nil || enc == nil
+}
+
+// Convert output of charset to utf-8. Bytes which can not be decoded are replaced by U+FFFD,
+// count of replaced chars is returned. Unsupported charset is handled as utf-8
+func ToUtf8(data string, name string) (string, int) {
+	enc, err := lookup(name)
+	if err != nil || enc == nil {
+		return validUtf8(data)
+	}
+
+	res, err := enc.NewDecoder().String(data)
+	if err != nil {
+		return validUtf8(data)
+	}
+
+	// U+FFFD can not be encoded by charsets other than utf-8, so all of them are replacements
+	return res, strings.Count(res, string(utf8.RuneError))
+}
+
+// nil encoding for utf-8
+func lookup(name string) (encoding.Encoding, error) {
+	if name == "" || strings.EqualFold(name, "utf8") {
+		return nil, nil
+	}
+
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil {
+		return nil, err
+	}
+	if enc == nil {
+		return nil, fmt.Errorf("charset is not supported. Name: %v", name)
+	}
+	if enc == unicode.UTF8 {
+		return nil, nil
+	}
+
+	return enc, nil
+}
+
+// Every invalid byte is replaced as by json encoder
+func validUtf8(data string) (string, int) {
+	if utf8.ValidString(data) {
+		return data, 0
+	}
+
+	var res strings.Builder
+	res.Grow(len(data))
+
+	replaced := 0
+	for idx := 0; idx < len(data); {
+		r, size := utf8.DecodeRuneInString(data[idx:])
+		if r == utf8.RuneError && size == 1 {
+			replaced++
+		}
+		res.WriteRune(r)
+		idx += size
+	}
+
+	return res.String(), replaced
+}
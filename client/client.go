@@ -0,0 +1,228 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+const (
+	sessionsPath = "/api/v2/sessions"
+	commandsPath = "commands"
+	stdinPath    = "stdin"
+	outputsPath  = "/api/v2/outputs"
+
+	contentTypeHeader  = "Content-Type"
+	contentTypeAppJson = "application/json"
+)
+
+// Create client of CmdProxy v2 API. baseUrl e.g. http://localhost:25505.
+// http.DefaultClient is used if httpClient is nil. Idempotent requests are retried maxRetries times
+func NewClient(baseUrl string, httpClient *http.Client, maxRetries int, retryDelay time.Duration) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	return &Client{
+		baseUrl:    strings.TrimRight(baseUrl, "/"),
+		httpClient: httpClient,
+		maxRetries: maxRetries,
+		retryDelay: retryDelay,
+	}
+}
+
+type Client struct {
+	baseUrl    string
+	httpClient *http.Client
+	maxRetries int
+	// delay before retry, multiplied by number of attempt
+	retryDelay time.Duration
+}
+
+func (o *Client) ConnectConsole(ctx context.Context, record bool) (model.SessionResource, error) {
+	return o.ConnectConsoleWithOptions(ctx, record, model.ConsoleOptions{})
+}
+
+// Run-as user should be allowed by service
+func (o *Client) ConnectConsoleWithOptions(ctx context.Context, record bool, options model.ConsoleOptions) (model.SessionResource, error) {
+	request := model.SessionCreateRequest{
+		Type:           model.SessionTypeConsole,
+		ConsoleOptions: options,
+	}
+	request.Record = record
+
+	return o.connect(ctx, request)
+}
+
+// Connection parameters are taken from inventory if Device is set
+func (o *Client) ConnectTelnet(ctx context.Context, telnetRequest model.ConnectTelnetRequest) (model.SessionResource, error) {
+	return o.connect(ctx, model.SessionCreateRequest{
+		Type:                 model.SessionTypeTelnet,
+		ConnectTelnetRequest: telnetRequest,
+	})
+}
+
+// Execute Command or Commands of request in session. SessionId of request is ignored
+func (o *Client) Command(ctx context.Context, sessID string, request model.CommandRequest) (model.CommandResponse, error) {
+	var response model.CommandResponse
+	err := o.do(ctx, http.MethodPost, sessionPath(sessID)+"/"+commandsPath, request, &response)
+
+	return response, err
+}
+
+// Execute single Command and return output bytes as is. OutputEncoding of request is ignored
+func (o *Client) CommandRaw(ctx context.Context, sessID string, request model.CommandRequest) ([]byte, error) {
+	request.OutputEncoding = model.OutputEncodingRaw
+
+	var response []byte
+	err := o.do(ctx, http.MethodPost, sessionPath(sessID)+"/"+commandsPath, request, &response)
+
+	return response, err
+}
+
+// Write input of console command started with KeepStdinOpen. SessionId of request is ignored
+func (o *Client) WriteStdin(ctx context.Context, sessID string, request model.StdinRequest) error {
+	return o.do(ctx, http.MethodPost, sessionPath(sessID)+"/"+stdinPath, request, nil)
+}
+
+// Page of whole output of truncated result by OutputId of response. Page is read to the end
+// of output if limit is 0. Size of whole output is OutputSize of response
+func (o *Client) Output(ctx context.Context, outputID string, offset int64, limit int64) ([]byte, error) {
+	path := fmt.Sprintf("%v/%v?offset=%v&limit=%v", outputsPath, url.PathEscape(outputID), offset, limit)
+
+	var response []byte
+	err := o.do(ctx, http.MethodGet, path, nil, &response)
+
+	return response, err
+}
+
+func (o *Client) Disconnect(ctx context.Context, sessID string) error {
+	return o.do(ctx, http.MethodDelete, sessionPath(sessID), nil, nil)
+}
+
+func (o *Client) Get(ctx context.Context, sessID string) (model.SessionResource, error) {
+	var response model.SessionResource
+	err := o.do(ctx, http.MethodGet, sessionPath(sessID), nil, &response)
+
+	return response, err
+}
+
+// Sessions of type ("console" or "telnet"). All sessions if type is empty
+func (o *Client) List(ctx context.Context, sessType string) ([]model.SessionResource, error) {
+	path := sessionsPath
+	if sessType != "" {
+		path += "?type=" + url.QueryEscape(sessType)
+	}
+
+	var response model.SessionListResponse
+	err := o.do(ctx, http.MethodGet, path, nil, &response)
+
+	return response.Sessions, err
+}
+
+func (o *Client) connect(ctx context.Context, request model.SessionCreateRequest) (model.SessionResource, error) {
+	var response model.SessionResource
+	err := o.do(ctx, http.MethodPost, sessionsPath, request, &response)
+
+	return response, err
+}
+
+// Send request and unmarshal response to result if it is not nil.
+// Body is copied as is if result is *[]byte. GET and DELETE are retried
+func (o *Client) do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("Client.do() Marshal request. Error: %v", err)
+		}
+	}
+
+	retries := 0
+	if method == http.MethodGet || method == http.MethodDelete {
+		retries = o.maxRetries
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var status int
+		status, err = o.send(ctx, method, path, bodyBytes, result)
+		if err == nil || attempt >= retries || (status != 0 && !isRetryable(status)) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(o.retryDelay * time.Duration(attempt+1)):
+		}
+	}
+}
+
+// Return status of response, 0 if request is not sent
+func (o *Client) send(ctx context.Context, method string, path string, body []byte, result interface{}) (int, error) {
+	request, err := http.NewRequest(method, o.baseUrl+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("Client.send() Create request. Error: %v", err)
+	}
+	request = request.WithContext(ctx)
+
+	if body != nil {
+		request.Header.Set(contentTypeHeader, contentTypeAppJson)
+	}
+
+	resp, err := o.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("Client.send() Read response. Error: %v", err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    http.StatusText(resp.StatusCode),
+		}
+
+		var errResp model.ErrorResponse
+		if json.Unmarshal(respBytes, &errResp) == nil && errResp.Error != "" {
+			apiErr.Message = errResp.Error
+		}
+
+		return resp.StatusCode, apiErr
+	}
+
+	if raw, ok := result.(*[]byte); ok {
+		*raw = respBytes
+
+		return resp.StatusCode, nil
+	}
+
+	if result != nil && len(respBytes) > 0 {
+		if err := json.Unmarshal(respBytes, result); err != nil {
+			return resp.StatusCode, fmt.Errorf("Client.send() Unmarshal response. Error: %v", err)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+func sessionPath(sessID string) string {
+	return sessionsPath + "/" + url.PathEscape(sessID)
+}
@@ -0,0 +1,210 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deminds/CmdProxy/client"
+	"github.com/deminds/CmdProxy/controller"
+	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/parser"
+	"github.com/deminds/CmdProxy/session"
+	"github.com/deminds/CmdProxy/session/types"
+)
+
+const (
+	testTimeoutSec = 10
+	testRetryDelay = 10 * time.Millisecond
+)
+
+// v2 API handler of real controller with console sessions only
+func newTestHandler(t *testing.T) http.Handler {
+	idGenerator := newTestIDGenerator(t)
+	consoleFactory := types.NewConsoleSessionFactory(idGenerator, testTimeoutSec, nil, nil, nil, nil)
+
+	httpController := controller.NewHttpController(session.NewSessionPool(), idGenerator, testTimeoutSec,
+		consoleFactory, nil, 1, nil, nil, nil, nil, nil, nil, parser.NewRegistry(), nil, 0)
+
+	h := http.NewServeMux()
+	h.HandleFunc(controller.V2SessionsPath, httpController.V2SessionsHandler)
+	h.HandleFunc(controller.V2SessionsPath+"/", httpController.V2SessionHandler)
+
+	return h
+}
+
+// Sonyflake needs private ip address of host for machine id
+func newTestIDGenerator(t *testing.T) (res *generatorid.IDGenerator) {
+	res = generatorid.NewIDGenerator()
+
+	defer func() {
+		if err := recover(); err != nil {
+			t.Skipf("ID generator is not available. Error: %v", err)
+		}
+	}()
+
+	if _, err := res.Next(); err != nil {
+		t.Skipf("ID generator is not available. Error: %v", err)
+	}
+
+	return res
+}
+
+// Proxy answers failStatuses to first requests, then passes requests to handler
+type failingProxy struct {
+	handler      http.Handler
+	failStatuses []int
+
+	mutex    sync.Mutex
+	requests []string
+}
+
+func (o *failingProxy) ServeHTTP(respWriter http.ResponseWriter, request *http.Request) {
+	o.mutex.Lock()
+	o.requests = append(o.requests, request.Method+" "+request.URL.Path)
+	attempt := len(o.requests)
+	o.mutex.Unlock()
+
+	if attempt <= len(o.failStatuses) {
+		respWriter.WriteHeader(o.failStatuses[attempt-1])
+
+		return
+	}
+
+	o.handler.ServeHTTP(respWriter, request)
+}
+
+func (o *failingProxy) count() int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return len(o.requests)
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *client.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return client.NewClient(server.URL, server.Client(), 2, testRetryDelay)
+}
+
+func TestClientSession(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, newTestHandler(t))
+
+	sess, err := c.ConnectConsole(ctx, false)
+	if err != nil {
+		t.Fatalf("ConnectConsole(). Error: %v", err)
+	}
+	if sess.Id == "" || sess.Type != model.SessionTypeConsole {
+		t.Fatalf("Wrong session: %+v", sess)
+	}
+
+	response, err := c.Command(ctx, sess.Id, model.CommandRequest{Command: "echo hello"})
+	if err != nil {
+		t.Fatalf("Command(). Error: %v", err)
+	}
+	if strings.TrimSpace(response.Output) != "hello" {
+		t.Fatalf("Wrong output: %q", response.Output)
+	}
+
+	sessions, err := c.List(ctx, model.SessionTypeConsole)
+	if err != nil {
+		t.Fatalf("List(). Error: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Id != sess.Id {
+		t.Fatalf("Wrong sessions: %+v", sessions)
+	}
+
+	if err := c.Disconnect(ctx, sess.Id); err != nil {
+		t.Fatalf("Disconnect(). Error: %v", err)
+	}
+
+	sessions, err = c.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List() after disconnect. Error: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("Session is not removed: %+v", sessions)
+	}
+}
+
+func TestClientAPIError(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, newTestHandler(t))
+
+	_, err := c.Get(ctx, "unknown")
+	if !client.IsNotFound(err) {
+		t.Fatalf("Expected not found. Error: %v", err)
+	}
+
+	// message is taken from ErrorResponse of controller
+	apiErr := err.(*client.APIError)
+	if apiErr.Message == "" || apiErr.Message == http.StatusText(http.StatusNotFound) {
+		t.Fatalf("Message is not taken from body: %q", apiErr.Message)
+	}
+
+	_, err = c.Command(ctx, "unknown", model.CommandRequest{})
+	if !client.IsBadRequest(err) {
+		t.Fatalf("Expected bad request. Error: %v", err)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	ctx := context.Background()
+	proxy := &failingProxy{
+		handler:      newTestHandler(t),
+		failStatuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway},
+	}
+	c := newTestClient(t, proxy)
+
+	if _, err := c.List(ctx, ""); err != nil {
+		t.Fatalf("List() is not retried. Error: %v", err)
+	}
+	if proxy.count() != 3 {
+		t.Fatalf("Wrong number of requests. Expected: 3, Actual: %v", proxy.count())
+	}
+}
+
+func TestClientRetryLimit(t *testing.T) {
+	ctx := context.Background()
+	proxy := &failingProxy{
+		handler:      newTestHandler(t),
+		failStatuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+	}
+	c := newTestClient(t, proxy)
+
+	_, err := c.List(ctx, "")
+	apiErr, ok := err.(*client.APIError)
+	if !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 after retries. Error: %v", err)
+	}
+	// body of proxy is not json, status text is used
+	if apiErr.Message != http.StatusText(http.StatusServiceUnavailable) {
+		t.Fatalf("Wrong message: %q", apiErr.Message)
+	}
+	if proxy.count() != 3 {
+		t.Fatalf("Wrong number of requests. Expected: 3, Actual: %v", proxy.count())
+	}
+}
+
+func TestClientNoRetryOfPost(t *testing.T) {
+	ctx := context.Background()
+	proxy := &failingProxy{
+		handler:      newTestHandler(t),
+		failStatuses: []int{http.StatusBadGateway},
+	}
+	c := newTestClient(t, proxy)
+
+	if _, err := c.ConnectConsole(ctx, false); !client.IsBadGateway(err) {
+		t.Fatalf("Expected bad gateway. Error: %v", err)
+	}
+	if proxy.count() != 1 {
+		t.Fatalf("POST is retried. Requests: %v", proxy.count())
+	}
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error returned by API with not successful status
+type APIError struct {
+	StatusCode int
+	// Error of model.ErrorResponse or status text if body is not json
+	Message string
+}
+
+func (o *APIError) Error() string {
+	return fmt.Sprintf("CmdProxy API error. Status: %v, Error: %v", o.StatusCode, o.Message)
+}
+
+// Session or other resource not found
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// Request was rejected as not valid
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
+
+// Device or command failed
+func IsBadGateway(err error) bool {
+	return hasStatus(err, http.StatusBadGateway)
+}
+
+func hasStatus(err error, status int) bool {
+	apiErr, ok := err.(*APIError)
+
+	return ok && apiErr.StatusCode == status
+}
+
+// Temporary errors of idempotent requests are retried
+func isRetryable(status int) bool {
+	return status == http.StatusBadGateway ||
+		status == http.StatusServiceUnavailable ||
+		status == http.StatusGatewayTimeout
+}
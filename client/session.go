@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+// Connected session. Close() disconnects it
+type Session struct {
+	client *Client
+	model.SessionResource
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (o *Client) OpenConsole(ctx context.Context, record bool) (*Session, error) {
+	return o.OpenConsoleWithOptions(ctx, record, model.ConsoleOptions{})
+}
+
+func (o *Client) OpenConsoleWithOptions(ctx context.Context, record bool, options model.ConsoleOptions) (*Session, error) {
+	resource, err := o.ConnectConsoleWithOptions(ctx, record, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{client: o, SessionResource: resource}, nil
+}
+
+func (o *Client) OpenTelnet(ctx context.Context, telnetRequest model.ConnectTelnetRequest) (*Session, error) {
+	resource, err := o.ConnectTelnet(ctx, telnetRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{client: o, SessionResource: resource}, nil
+}
+
+// Open console session, call fn and disconnect
+func (o *Client) WithConsole(ctx context.Context, record bool, fn func(*Session) error) error {
+	sess, err := o.OpenConsole(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	return sess.run(fn)
+}
+
+// Open telnet session, call fn and disconnect
+func (o *Client) WithTelnet(ctx context.Context, telnetRequest model.ConnectTelnetRequest, fn func(*Session) error) error {
+	sess, err := o.OpenTelnet(ctx, telnetRequest)
+	if err != nil {
+		return err
+	}
+
+	return sess.run(fn)
+}
+
+// Execute one command
+func (o *Session) Command(ctx context.Context, command string) (model.CommandResponse, error) {
+	return o.client.Command(ctx, o.Id, model.CommandRequest{Command: command})
+}
+
+// Execute request with any options
+func (o *Session) Execute(ctx context.Context, request model.CommandRequest) (model.CommandResponse, error) {
+	return o.client.Command(ctx, o.Id, request)
+}
+
+// Write data to input of running command. Input is closed if close is true
+func (o *Session) WriteStdin(ctx context.Context, data []byte, close bool) error {
+	return o.client.WriteStdin(ctx, o.Id, model.StdinRequest{
+		Stdin:         base64.StdEncoding.EncodeToString(data),
+		StdinEncoding: model.StdinEncodingBase64,
+		Close:         close,
+	})
+}
+
+// Disconnect session. Safe to call several times
+func (o *Session) Close() error {
+	o.closeOnce.Do(func() {
+		o.closeErr = o.client.Disconnect(context.Background(), o.Id)
+	})
+
+	return o.closeErr
+}
+
+// Error of fn is returned before error of Close
+func (o *Session) run(fn func(*Session) error) error {
+	err := fn(o)
+	closeErr := o.Close()
+
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+const (
+	v1Path = "/api/v1.0"
+
+	broadcastPath      = v1Path + "/telnet/broadcast"
+	devicesPath        = v1Path + "/inventory/devices"
+	devicePath         = v1Path + "/inventory/device"
+	backupRunPath      = v1Path + "/backup/run"
+	backupVersionsPath = v1Path + "/backup/versions"
+	backupConfigPath   = v1Path + "/backup/config"
+	backupDiffPath     = v1Path + "/backup/diff"
+	configPushPath     = v1Path + "/config/push"
+	jobsPath           = v1Path + "/jobs"
+	jobPath            = v1Path + "/job"
+	templatesPath      = v1Path + "/templates"
+	templatePath       = v1Path + "/template"
+	webhooksPath       = v1Path + "/webhooks"
+	recordingPath      = v1Path + "/sessions/%v/recording"
+	openApiPath        = "/api/v2/openapi.json"
+)
+
+// Execute commands on several telnet targets. Streaming of request is not supported
+func (o *Client) Broadcast(ctx context.Context, request model.BroadcastRequest) (model.BroadcastResponse, error) {
+	request.Stream = false
+
+	var response model.BroadcastResponse
+	err := o.do(ctx, http.MethodPost, broadcastPath, request, &response)
+
+	return response, err
+}
+
+// Inventory devices with any of tags or groups. All devices if both are empty
+func (o *Client) Devices(ctx context.Context, tags []string, groups []string) ([]model.Device, error) {
+	query := url.Values{}
+	query["tag"] = tags
+	query["group"] = groups
+
+	var response model.DeviceListResponse
+	err := o.do(ctx, http.MethodGet, withQuery(devicesPath, query), nil, &response)
+
+	return response.Devices, err
+}
+
+func (o *Client) Device(ctx context.Context, name string) (model.Device, error) {
+	var response model.Device
+	err := o.do(ctx, http.MethodGet, withQuery(devicePath, url.Values{"name": {name}}), nil, &response)
+
+	return response, err
+}
+
+// Add or replace inventory device
+func (o *Client) PutDevice(ctx context.Context, device model.Device) (model.Device, error) {
+	var response model.Device
+	err := o.do(ctx, http.MethodPost, devicePath, device, &response)
+
+	return response, err
+}
+
+func (o *Client) DeleteDevice(ctx context.Context, name string) error {
+	return o.do(ctx, http.MethodDelete, withQuery(devicePath, url.Values{"name": {name}}), nil, nil)
+}
+
+func (o *Client) Backup(ctx context.Context, request model.BackupRequest) (model.BackupResponse, error) {
+	var response model.BackupResponse
+	err := o.do(ctx, http.MethodPost, backupRunPath, request, &response)
+
+	return response, err
+}
+
+func (o *Client) BackupVersions(ctx context.Context, device string) (model.BackupVersionListResponse, error) {
+	var response model.BackupVersionListResponse
+	err := o.do(ctx, http.MethodGet, withQuery(backupVersionsPath, url.Values{"device": {device}}), nil, &response)
+
+	return response, err
+}
+
+// Backed up config of device. The last version if version is empty
+func (o *Client) BackupConfig(ctx context.Context, device string, version string) (string, error) {
+	query := url.Values{"device": {device}}
+	if version != "" {
+		query.Set("version", version)
+	}
+
+	var response []byte
+	err := o.do(ctx, http.MethodGet, withQuery(backupConfigPath, query), nil, &response)
+
+	return string(response), err
+}
+
+// Unified diff between two versions of device config
+func (o *Client) BackupDiff(ctx context.Context, device string, from string, to string) (string, error) {
+	query := url.Values{"device": {device}, "from": {from}, "to": {to}}
+
+	var response []byte
+	err := o.do(ctx, http.MethodGet, withQuery(backupDiffPath, query), nil, &response)
+
+	return string(response), err
+}
+
+func (o *Client) PushConfig(ctx context.Context, request model.ConfigPushRequest) (model.ConfigPushResponse, error) {
+	var response model.ConfigPushResponse
+	err := o.do(ctx, http.MethodPost, configPushPath, request, &response)
+
+	return response, err
+}
+
+func (o *Client) Jobs(ctx context.Context) ([]model.ScheduledJob, error) {
+	var response model.JobListResponse
+	err := o.do(ctx, http.MethodGet, jobsPath, nil, &response)
+
+	return response.Jobs, err
+}
+
+// Id of job is generated by service
+func (o *Client) AddJob(ctx context.Context, job model.ScheduledJob) (model.ScheduledJob, error) {
+	var response model.ScheduledJob
+	err := o.do(ctx, http.MethodPost, jobsPath, job, &response)
+
+	return response, err
+}
+
+// Job with the last runs
+func (o *Client) Job(ctx context.Context, id string) (model.JobResponse, error) {
+	var response model.JobResponse
+	err := o.do(ctx, http.MethodGet, withQuery(jobPath, url.Values{"id": {id}}), nil, &response)
+
+	return response, err
+}
+
+func (o *Client) DeleteJob(ctx context.Context, id string) error {
+	return o.do(ctx, http.MethodDelete, withQuery(jobPath, url.Values{"id": {id}}), nil, nil)
+}
+
+func (o *Client) Templates(ctx context.Context) ([]string, error) {
+	var response model.ParseTemplateListResponse
+	err := o.do(ctx, http.MethodGet, templatesPath, nil, &response)
+
+	return response.Templates, err
+}
+
+func (o *Client) Template(ctx context.Context, name string) (model.ParseTemplate, error) {
+	var response model.ParseTemplate
+	err := o.do(ctx, http.MethodGet, withQuery(templatePath, url.Values{"name": {name}}), nil, &response)
+
+	return response, err
+}
+
+// Add or replace template
+func (o *Client) PutTemplate(ctx context.Context, template model.ParseTemplate) error {
+	return o.do(ctx, http.MethodPost, templatePath, template, nil)
+}
+
+func (o *Client) DeleteTemplate(ctx context.Context, name string) error {
+	return o.do(ctx, http.MethodDelete, withQuery(templatePath, url.Values{"name": {name}}), nil, nil)
+}
+
+// Secrets of webhooks are not returned
+func (o *Client) Webhooks(ctx context.Context) ([]model.Webhook, error) {
+	var response model.WebhookListResponse
+	err := o.do(ctx, http.MethodGet, webhooksPath, nil, &response)
+
+	return response.Webhooks, err
+}
+
+// Id of webhook is generated by service
+func (o *Client) AddWebhook(ctx context.Context, hook model.Webhook) (model.Webhook, error) {
+	var response model.Webhook
+	err := o.do(ctx, http.MethodPost, webhooksPath, hook, &response)
+
+	return response, err
+}
+
+func (o *Client) DeleteWebhook(ctx context.Context, id string) error {
+	return o.do(ctx, http.MethodDelete, withQuery(webhooksPath, url.Values{"id": {id}}), nil, nil)
+}
+
+// Session transcript in asciicast v2 format
+func (o *Client) Recording(ctx context.Context, sessID string) ([]byte, error) {
+	var response []byte
+	err := o.do(ctx, http.MethodGet, fmt.Sprintf(recordingPath, url.PathEscape(sessID)), nil, &response)
+
+	return response, err
+}
+
+// OpenAPI document of v2 API
+func (o *Client) OpenApi(ctx context.Context) ([]byte, error) {
+	var response []byte
+	err := o.do(ctx, http.MethodGet, openApiPath, nil, &response)
+
+	return response, err
+}
+
+func withQuery(path string, query url.Values) string {
+	if encoded := query.Encode(); encoded != "" {
+		return path + "?" + encoded
+	}
+
+	return path
+}
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+// Comma separated flag value. Empty items are skipped
+func splitList(value string) []string {
+	res := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
+
+// Write data to file or stdout if path is empty
+func writeData(o *app, path string, data []byte) error {
+	if path == "" {
+		_, err := o.stdout.Write(data)
+
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Split "<action> [flags] [args]" of commands with actions
+func action(args []string, actions ...string) (string, []string, error) {
+	if len(args) > 0 {
+		for _, name := range actions {
+			if args[0] == name {
+				return name, args[1:], nil
+			}
+		}
+	}
+
+	return "", nil, fmt.Errorf("Action expected: %v", strings.Join(actions, ", "))
+}
+
+func runBroadcast(o *app, args []string) error {
+	flags := newFlagSet("broadcast")
+	devices := flags.String("device", "", "Comma separated inventory devices")
+	tags := flags.String("tag", "", "Comma separated tags of inventory devices")
+	groups := flags.String("group", "", "Comma separated groups of inventory devices")
+	concurrency := flags.Int("concurrency", 0, "Max devices processed at the same time. Service default if 0")
+	cmdFlags := addCommandFlags(flags)
+	flags.Parse(args)
+
+	cmdRequest, err := cmdFlags.request(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	commands := cmdRequest.Commands
+	if cmdRequest.Command != "" {
+		commands = []string{cmdRequest.Command}
+	}
+
+	response, err := o.client.Broadcast(o.ctx, model.BroadcastRequest{
+		Commands:    commands,
+		Devices:     splitList(*devices),
+		Tags:        splitList(*tags),
+		Groups:      splitList(*groups),
+		Concurrency: *concurrency,
+		Raw:         cmdRequest.Raw,
+	})
+	if err != nil {
+		return err
+	}
+
+	return o.print(response, func(w io.Writer) {
+		for _, res := range response.Results {
+			name := res.Device
+			if name == "" {
+				name = fmt.Sprintf("%v:%v", res.Host, res.Port)
+			}
+
+			fmt.Fprintf(w, "=== %v %v\n", name, res.Status)
+			if res.Error != "" {
+				fmt.Fprintf(w, "Error: %v\n", res.Error)
+			}
+			writeResults(w, res.Commands)
+		}
+	})
+}
+
+func runDevices(o *app, args []string) error {
+	flags := newFlagSet("devices")
+	tags := flags.String("tag", "", "Comma separated tags")
+	groups := flags.String("group", "", "Comma separated groups")
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 0); err != nil {
+		return err
+	}
+
+	devices, err := o.client.Devices(o.ctx, splitList(*tags), splitList(*groups))
+	if err != nil {
+		return err
+	}
+
+	return o.print(devices, func(w io.Writer) {
+		for _, device := range devices {
+			fmt.Fprintf(w, "%v\t%v:%v\t%v\n", device.Name, device.Host, device.Port, strings.Join(device.Tags, ","))
+		}
+	})
+}
+
+func runDevice(o *app, args []string) error {
+	name, args, err := action(args, "get", "put", "delete")
+	if err != nil {
+		return err
+	}
+
+	flags := newFlagSet("device " + name)
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 1); err != nil {
+		return err
+	}
+
+	switch name {
+	case "get":
+		device, err := o.client.Device(o.ctx, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		return o.print(device, nil)
+	case "put":
+		var device model.Device
+		if err := readJsonFile(flags.Arg(0), &device); err != nil {
+			return err
+		}
+
+		device, err = o.client.PutDevice(o.ctx, device)
+		if err != nil {
+			return err
+		}
+
+		return o.print(device, nil)
+	default:
+		return o.client.DeleteDevice(o.ctx, flags.Arg(0))
+	}
+}
+
+func runBackup(o *app, args []string) error {
+	name, args, err := action(args, "run", "versions", "config", "diff")
+	if err != nil {
+		return err
+	}
+
+	flags := newFlagSet("backup " + name)
+
+	switch name {
+	case "run":
+		devices := flags.String("device", "", "Comma separated inventory devices")
+		tags := flags.String("tag", "", "Comma separated tags")
+		groups := flags.String("group", "", "Comma separated groups")
+		flags.Parse(args)
+
+		response, err := o.client.Backup(o.ctx, model.BackupRequest{
+			Devices: splitList(*devices),
+			Tags:    splitList(*tags),
+			Groups:  splitList(*groups),
+		})
+		if err != nil {
+			return err
+		}
+
+		return o.print(response, func(w io.Writer) {
+			for _, res := range response.Results {
+				fmt.Fprintf(w, "%v\t%v\t%v\tchanged=%v\t%v\n", res.Device, res.Status, res.Version, res.Changed, res.Error)
+			}
+		})
+	case "versions":
+		flags.Parse(args)
+		if err := checkArgs(flags, 1); err != nil {
+			return err
+		}
+
+		response, err := o.client.BackupVersions(o.ctx, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		return o.print(response, func(w io.Writer) {
+			for _, version := range response.Versions {
+				fmt.Fprintf(w, "%v\t%v\t%v\n", version.Version, version.Time.Format("2006-01-02 15:04:05"), version.Size)
+			}
+		})
+	case "config":
+		flags.Parse(args)
+		if flags.NArg() != 1 && flags.NArg() != 2 {
+			return checkArgs(flags, 1)
+		}
+
+		config, err := o.client.BackupConfig(o.ctx, flags.Arg(0), flags.Arg(1))
+		if err != nil {
+			return err
+		}
+
+		return writeData(o, "", []byte(config))
+	default:
+		flags.Parse(args)
+		if err := checkArgs(flags, 3); err != nil {
+			return err
+		}
+
+		diff, err := o.client.BackupDiff(o.ctx, flags.Arg(0), flags.Arg(1), flags.Arg(2))
+		if err != nil {
+			return err
+		}
+
+		return writeData(o, "", []byte(diff))
+	}
+}
+
+func runPush(o *app, args []string) error {
+	flags := newFlagSet("push")
+	rollback := flags.String("rollback", "", "Rollback on error: none, snapshot or native")
+	file := flags.String("file", "", "File with config lines, '-' for stdin")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return checkArgs(flags, 1)
+	}
+
+	request := model.ConfigPushRequest{
+		Device:   flags.Arg(0),
+		Rollback: *rollback,
+	}
+
+	if *file != "" {
+		lines, err := readLines(*file)
+		if err != nil {
+			return err
+		}
+
+		request.Lines = lines
+	}
+	if line := strings.Join(flags.Args()[1:], " "); line != "" {
+		request.Lines = append(request.Lines, line)
+	}
+
+	response, err := o.client.PushConfig(o.ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if err := o.print(response, func(w io.Writer) {
+		fmt.Fprintf(w, "%v %v\n", response.Device, response.Status)
+		writeResults(w, response.Applied)
+		if response.Error != "" {
+			fmt.Fprintf(w, "Error: %v\nNot applied: %v\nRolled back: %v %v\n",
+				response.Error, len(response.NotApplied), response.RolledBack, response.RollbackError)
+		}
+	}); err != nil {
+		return err
+	}
+
+	if response.Status == model.Error {
+		return fmt.Errorf("Push failed: %v", response.Error)
+	}
+
+	return nil
+}
+
+func runJobs(o *app, args []string) error {
+	name, args, err := action(args, "list", "add", "get", "delete")
+	if err != nil {
+		return err
+	}
+
+	flags := newFlagSet("jobs " + name)
+	flags.Parse(args)
+
+	if name == "list" {
+		if err := checkArgs(flags, 0); err != nil {
+			return err
+		}
+
+		jobs, err := o.client.Jobs(o.ctx)
+		if err != nil {
+			return err
+		}
+
+		return o.print(jobs, func(w io.Writer) {
+			for _, job := range jobs {
+				fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", job.Id, job.Cron, job.Target, job.Name)
+			}
+		})
+	}
+
+	if err := checkArgs(flags, 1); err != nil {
+		return err
+	}
+
+	switch name {
+	case "add":
+		var job model.ScheduledJob
+		if err := readJsonFile(flags.Arg(0), &job); err != nil {
+			return err
+		}
+
+		job, err = o.client.AddJob(o.ctx, job)
+		if err != nil {
+			return err
+		}
+
+		return printId(o, job, job.Id)
+	case "get":
+		job, err := o.client.Job(o.ctx, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		return o.print(job, nil)
+	default:
+		return o.client.DeleteJob(o.ctx, flags.Arg(0))
+	}
+}
+
+func runTemplates(o *app, args []string) error {
+	name, args, err := action(args, "list", "get", "put", "delete")
+	if err != nil {
+		return err
+	}
+
+	flags := newFlagSet("templates " + name)
+	flags.Parse(args)
+
+	switch name {
+	case "list":
+		if err := checkArgs(flags, 0); err != nil {
+			return err
+		}
+
+		templates, err := o.client.Templates(o.ctx)
+		if err != nil {
+			return err
+		}
+
+		return o.print(templates, func(w io.Writer) {
+			for _, template := range templates {
+				fmt.Fprintln(w, template)
+			}
+		})
+	case "get":
+		if err := checkArgs(flags, 1); err != nil {
+			return err
+		}
+
+		template, err := o.client.Template(o.ctx, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		return o.print(template, func(w io.Writer) {
+			writeOutput(w, template.Template)
+		})
+	case "put":
+		if err := checkArgs(flags, 2); err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(flags.Arg(1))
+		if err != nil {
+			return err
+		}
+
+		return o.client.PutTemplate(o.ctx, model.ParseTemplate{Name: flags.Arg(0), Template: string(data)})
+	default:
+		if err := checkArgs(flags, 1); err != nil {
+			return err
+		}
+
+		return o.client.DeleteTemplate(o.ctx, flags.Arg(0))
+	}
+}
+
+func runWebhooks(o *app, args []string) error {
+	name, args, err := action(args, "list", "add", "delete")
+	if err != nil {
+		return err
+	}
+
+	flags := newFlagSet("webhooks " + name)
+
+	switch name {
+	case "list":
+		flags.Parse(args)
+		if err := checkArgs(flags, 0); err != nil {
+			return err
+		}
+
+		hooks, err := o.client.Webhooks(o.ctx)
+		if err != nil {
+			return err
+		}
+
+		return o.print(hooks, func(w io.Writer) {
+			for _, hook := range hooks {
+				events := make([]string, 0, len(hook.Events))
+				for _, event := range hook.Events {
+					events = append(events, string(event))
+				}
+
+				fmt.Fprintf(w, "%v\t%v\t%v\n", hook.Id, hook.Url, strings.Join(events, ","))
+			}
+		})
+	case "add":
+		secret := flags.String("secret", "", "Secret of HMAC-SHA256 signature")
+		events := flags.String("event", "", "Comma separated event types. All events if empty")
+		flags.Parse(args)
+		if err := checkArgs(flags, 1); err != nil {
+			return err
+		}
+
+		hook := model.Webhook{
+			Url:    flags.Arg(0),
+			Secret: *secret,
+		}
+		for _, event := range splitList(*events) {
+			hook.Events = append(hook.Events, model.EventType(event))
+		}
+
+		hook, err = o.client.AddWebhook(o.ctx, hook)
+		if err != nil {
+			return err
+		}
+
+		return printId(o, hook, hook.Id)
+	default:
+		flags.Parse(args)
+		if err := checkArgs(flags, 1); err != nil {
+			return err
+		}
+
+		return o.client.DeleteWebhook(o.ctx, flags.Arg(0))
+	}
+}
+
+func runOpenApi(o *app, args []string) error {
+	flags := newFlagSet("openapi")
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 0); err != nil {
+		return err
+	}
+
+	data, err := o.client.OpenApi(o.ctx)
+	if err != nil {
+		return err
+	}
+
+	return writeData(o, "", data)
+}
+
+// Print created resource as json or its id as text
+func printId(o *app, value interface{}, id string) error {
+	return o.print(value, func(w io.Writer) {
+		fmt.Fprintln(w, id)
+	})
+}
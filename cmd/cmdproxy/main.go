@@ -0,0 +1,162 @@
+// Command line client of CmdProxy.
+//
+// Usage: cmdproxy [global flags] <command> [flags] [args]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/deminds/CmdProxy/client"
+)
+
+const (
+	OutputJson = "json"
+	OutputText = "text"
+
+	retryDelay = 500 * time.Millisecond
+)
+
+type command struct {
+	usage string
+	run   func(o *app, args []string) error
+}
+
+var commands = map[string]command{
+	"connect":    {"connect [target flags]. Open session and print its id", runConnect},
+	"command":    {"command [-file path] [-raw] [-parse template] [-continue] [-stdin path] [-keep-stdin] [-encoding utf8|base64|raw] [-max-output n] <session> [command]", runCommand},
+	"stdin":      {"stdin [-close] <session> [path]. Write file to input of running command, '-' for stdin", runStdin},
+	"output":     {"output [-offset n] [-limit n] [-o path] <output id>. Read whole output of truncated result", runOutput},
+	"disconnect": {"disconnect <session>", runDisconnect},
+	"session":    {"session <session>", runSession},
+	"sessions":   {"sessions [-type console|telnet]", runSessions},
+	"exec":       {"exec [target flags] [-file path] [-raw] [-parse template] [-stdin path] [-encoding utf8|base64|raw] [-max-output n] [command]. Connect, execute and disconnect", runExec},
+	"repl":       {"repl [target flags]. Interactive session, :help for commands", runRepl},
+	"broadcast":  {"broadcast [-device names] [-tag tags] [-group groups] [-concurrency n] [-raw] [-file path] [command]", runBroadcast},
+	"devices":    {"devices [-tag tags] [-group groups]", runDevices},
+	"device":     {"device get <name> | put <file> | delete <name>", runDevice},
+	"backup":     {"backup run [-device names] [-tag tags] [-group groups] | versions <device> | config <device> [version] | diff <device> <from> <to>", runBackup},
+	"push":       {"push [-rollback none|snapshot|native] [-file path] <device> [line]", runPush},
+	"jobs":       {"jobs list | add <file> | get <id> | delete <id>", runJobs},
+	"templates":  {"templates list | get <name> | put <name> <file> | delete <name>", runTemplates},
+	"webhooks":   {"webhooks list | add [-secret secret] [-event types] <url> | delete <id>", runWebhooks},
+	"recording":  {"recording [-o path] <session>. Download asciicast recording", runRecording},
+	"openapi":    {"openapi. Print OpenAPI document of v2 API", runOpenApi},
+}
+
+type app struct {
+	ctx     context.Context
+	client  *client.Client
+	profile Profile
+	output  string
+	stdout  io.Writer
+}
+
+// Global flags. glog flags of imported packages are not used by cli
+var globalFlags = flag.NewFlagSet("cmdproxy", flag.ExitOnError)
+
+func main() {
+	configPath := globalFlags.String("config", defaultConfigPath(), "Config file with profiles. Env: "+ConfigEnv)
+	profileName := globalFlags.String("profile", "", "Profile of config. Default profile of config if empty. Env: "+ProfileEnv)
+	url := globalFlags.String("url", "", "CmdProxy url. Default: "+DefaultUrl)
+	output := globalFlags.String("output", "", "Output format: json or text. Default: text")
+	timeout := globalFlags.Duration("timeout", 0, fmt.Sprintf("Timeout of one request. Default: %v", DefaultTimeout))
+	retries := globalFlags.Int("retries", 0, "Retries of idempotent requests")
+	globalFlags.Usage = usage
+	globalFlags.Parse(os.Args[1:])
+
+	if globalFlags.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[globalFlags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command: %v\n", globalFlags.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	profile, err := loadProfile(*configPath, *profileName)
+	if err != nil {
+		fail(err)
+	}
+
+	if *url != "" {
+		profile.Url = *url
+	}
+	if *output != "" {
+		profile.Output = *output
+	}
+	globalFlags.Visit(func(f *flag.Flag) {
+		if f.Name == "retries" {
+			profile.Retries = *retries
+		}
+	})
+
+	requestTimeout := DefaultTimeout
+	if profile.Timeout != "" {
+		if requestTimeout, err = time.ParseDuration(profile.Timeout); err != nil {
+			fail(fmt.Errorf("Wrong timeout of profile: %v", profile.Timeout))
+		}
+	}
+	if *timeout > 0 {
+		requestTimeout = *timeout
+	}
+
+	if profile.Url == "" {
+		profile.Url = DefaultUrl
+	}
+	if profile.Output == "" {
+		profile.Output = OutputText
+	}
+	if profile.Output != OutputJson && profile.Output != OutputText {
+		fail(fmt.Errorf("Wrong output format: %v", profile.Output))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	o := &app{
+		ctx:     ctx,
+		client:  client.NewClient(profile.Url, &http.Client{Timeout: requestTimeout}, profile.Retries, retryDelay),
+		profile: profile,
+		output:  profile.Output,
+		stdout:  os.Stdout,
+	}
+
+	if err := cmd.run(o, globalFlags.Args()[1:]); err != nil {
+		stop()
+		fail(err)
+	}
+}
+
+func usage() {
+	out := globalFlags.Output()
+	fmt.Fprintf(out, "Usage: cmdproxy [global flags] <command> [flags] [args]\n\nCommands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(out, "  %v\n", commands[name].usage)
+	}
+
+	fmt.Fprintf(out, "\nTarget flags of connect, exec and repl override target of profile. See: cmdproxy connect -h\n\nGlobal flags:\n")
+	globalFlags.PrintDefaults()
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "cmdproxy: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+// Print value as indented json or with text printer
+func (o *app) print(value interface{}, text func(w io.Writer)) error {
+	if o.output == OutputJson || text == nil {
+		encoder := json.NewEncoder(o.stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(value)
+	}
+
+	text(o.stdout)
+
+	return nil
+}
+
+// Print output of single command or results of commands. Error is returned if any command failed
+func (o *app) printCommand(response model.CommandResponse) error {
+	err := o.print(response, func(w io.Writer) {
+		if len(response.Results) > 0 {
+			writeResults(w, response.Results)
+
+			return
+		}
+
+		writeOutput(w, response.Output)
+		writeTruncated(response.TruncatedOutput)
+		if response.ParseError != "" {
+			fmt.Fprintf(w, "Parse error: %v\n", response.ParseError)
+		}
+		for _, row := range response.Parsed {
+			writeRow(w, row)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, res := range response.Results {
+		if res.Status == model.Error {
+			return fmt.Errorf("Command failed: %v", res.Command)
+		}
+	}
+
+	return nil
+}
+
+func writeResults(w io.Writer, results []model.CommandResult) {
+	for _, res := range results {
+		fmt.Fprintf(w, "### %v\n", res.Command)
+		if res.Status == model.Error {
+			fmt.Fprintf(w, "Error: %v\n", res.Error)
+
+			continue
+		}
+
+		writeOutput(w, res.Output)
+		writeTruncated(res.TruncatedOutput)
+	}
+}
+
+// Note about truncated output is written to stderr, so output can be redirected to file
+func writeTruncated(truncated model.TruncatedOutput) {
+	if !truncated.Truncated {
+		return
+	}
+
+	if truncated.OutputId == "" {
+		fmt.Fprintf(os.Stderr, "Output is truncated. Size: %v bytes\n", truncated.OutputSize)
+
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Output is truncated. Size: %v bytes, whole output: cmdproxy output %v\n", truncated.OutputSize, truncated.OutputId)
+}
+
+// Output with trailing new line
+func writeOutput(w io.Writer, output string) {
+	if output == "" {
+		return
+	}
+
+	fmt.Fprint(w, output)
+	if !strings.HasSuffix(output, "\n") {
+		fmt.Fprintln(w)
+	}
+}
+
+// Parsed row as one json line, keys are sorted by encoder
+func writeRow(w io.Writer, row map[string]interface{}) {
+	rowBytes, err := json.Marshal(row)
+	if err != nil {
+		fmt.Fprintf(w, "%v\n", row)
+
+		return
+	}
+
+	fmt.Fprintf(w, "%s\n", rowBytes)
+}
+
+// Non empty lines of file, lines started with '#' are skipped. "-" is stdin
+func readLines(path string) ([]string, error) {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		reader = file
+	}
+
+	lines := []string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+// Content of file. "-" is stdin
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+
+	return ioutil.ReadFile(path)
+}
+
+// Unmarshal json file to value. "-" is stdin
+func readJsonFile(path string, value interface{}) error {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		reader = file
+	}
+
+	if err := json.NewDecoder(reader).Decode(value); err != nil {
+		return fmt.Errorf("Parse %v. Error: %v", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+const (
+	ConfigFileName = ".cmdproxy.json"
+	ConfigEnv      = "CMDPROXY_CONFIG"
+	ProfileEnv     = "CMDPROXY_PROFILE"
+
+	DefaultUrl     = "http://localhost:25505"
+	DefaultTimeout = time.Minute
+
+	TargetConsole = "console"
+	TargetTelnet  = "telnet"
+)
+
+// Dotfile with named profiles, ~/.cmdproxy.json by default
+type Config struct {
+	// Profile used if profile is not selected by flag or env
+	Default  string             `json:"default,omitempty"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Service and target of commands. Flags override profile settings
+type Profile struct {
+	Url string `json:"url,omitempty"`
+	// "json" or "text"
+	Output string `json:"output,omitempty"`
+	// Timeout of one request to service, e.g. "30s"
+	Timeout string `json:"timeout,omitempty"`
+	Retries int    `json:"retries,omitempty"`
+
+	// "console" or "telnet". Target of exec and repl
+	Target string `json:"target,omitempty"`
+	Record bool   `json:"record,omitempty"`
+	// Process settings of console target
+	Console model.ConsoleOptions `json:"console,omitempty"`
+	// Telnet target. Settings of inventory device are used if Device is set
+	Telnet model.ConnectTelnetRequest `json:"telnet,omitempty"`
+}
+
+// Path from env or file in home dir
+func defaultConfigPath() string {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ConfigFileName
+	}
+
+	return filepath.Join(home, ConfigFileName)
+}
+
+// Load profile by name. Empty profile is returned if config file does not exist and name is empty
+func loadProfile(path string, name string) (Profile, error) {
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && name == "" {
+		return Profile{}, nil
+	}
+	if err != nil {
+		return Profile{}, fmt.Errorf("loadProfile() Read config. Path: %v, Error: %v", path, err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Profile{}, fmt.Errorf("loadProfile() Parse config. Path: %v, Error: %v", path, err)
+	}
+
+	if name == "" {
+		name = config.Default
+	}
+	if name == "" {
+		return Profile{}, nil
+	}
+
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("loadProfile() Profile not found. Path: %v, Profile: %v", path, name)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/deminds/CmdProxy/client"
+	"github.com/deminds/CmdProxy/model"
+)
+
+const (
+	replPrompt    = "cmdproxy> "
+	replCmdPrefix = ":"
+	replHelp      = `Lines are executed in session. REPL commands:
+  :raw on|off         return telnet output as is
+  :parse <name>|off   parse output with template
+  :output json|text   output format
+  :run <file>         execute commands of file
+  :session            print session id
+  :help               print this help
+  :quit               disconnect and exit
+`
+)
+
+// Interactive session. Session is disconnected on :quit, end of input or interrupt
+func runRepl(o *app, args []string) error {
+	flags := newFlagSet("repl")
+	profile := o.profile
+	addTargetFlags(flags, &profile)
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 0); err != nil {
+		return err
+	}
+
+	sess, err := o.open(profile)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	fmt.Fprintf(os.Stderr, "Connected. Session: %v. Type :help for help\n", sess.Id)
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	repl := &replState{app: o, sess: sess, prompt: replPrompt}
+	for {
+		fmt.Fprint(o.stdout, repl.prompt)
+
+		select {
+		case <-o.ctx.Done():
+			fmt.Fprintln(o.stdout)
+
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				fmt.Fprintln(o.stdout)
+
+				return nil
+			}
+
+			quit, err := repl.handle(strings.TrimSpace(line))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+				// session was dropped by service, e.g. by timeout between commands
+				if client.IsNotFound(err) {
+					return fmt.Errorf("Session is closed: %v", sess.Id)
+				}
+			}
+			if quit {
+				return nil
+			}
+		}
+	}
+}
+
+type replState struct {
+	*app
+	sess *client.Session
+
+	raw    bool
+	parse  string
+	prompt string
+}
+
+// Execute line. true is returned if REPL should exit
+func (o *replState) handle(line string) (bool, error) {
+	if line == "" {
+		return false, nil
+	}
+
+	if !strings.HasPrefix(line, replCmdPrefix) {
+		return false, o.execute(model.CommandRequest{Command: line, Parse: o.parse})
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, replCmdPrefix))
+	arg := ""
+	if len(fields) > 1 {
+		arg = fields[1]
+	}
+
+	switch fields[0] {
+	case "quit", "exit":
+		return true, nil
+	case "help":
+		fmt.Fprint(o.stdout, replHelp)
+	case "session":
+		fmt.Fprintln(o.stdout, o.sess.Id)
+	case "raw":
+		if arg != "on" && arg != "off" {
+			return false, fmt.Errorf("Expected: :raw on|off")
+		}
+
+		o.raw = arg == "on"
+	case "parse":
+		if arg == "" {
+			return false, fmt.Errorf("Expected: :parse <name>|off")
+		}
+
+		o.parse = arg
+		if arg == "off" {
+			o.parse = ""
+		}
+	case "output":
+		if arg != OutputJson && arg != OutputText {
+			return false, fmt.Errorf("Expected: :output json|text")
+		}
+
+		o.output = arg
+	case "run":
+		if arg == "" {
+			return false, fmt.Errorf("Expected: :run <file>")
+		}
+
+		commands, err := readLines(arg)
+		if err != nil {
+			return false, err
+		}
+
+		return false, o.execute(model.CommandRequest{Commands: commands})
+	default:
+		return false, fmt.Errorf("Unknown command: %v. Type :help for help", line)
+	}
+
+	return false, nil
+}
+
+func (o *replState) execute(request model.CommandRequest) error {
+	request.Raw = o.raw
+
+	response, err := o.sess.Execute(o.ctx, request)
+	if err != nil {
+		return err
+	}
+
+	prompt := response.Prompt
+	if len(response.Results) > 0 {
+		prompt = response.Results[len(response.Results)-1].Prompt
+	}
+	if prompt != "" {
+		o.prompt = prompt + " "
+	}
+
+	return o.printCommand(response)
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+// Options of command request set by flags
+type commandFlags struct {
+	file            string
+	raw             bool
+	parse           string
+	onErrorContinue bool
+	stdin           string
+	keepStdinOpen   bool
+	encoding        string
+	maxOutput       int64
+}
+
+func addCommandFlags(flags *flag.FlagSet) *commandFlags {
+	res := &commandFlags{}
+	flags.StringVar(&res.file, "file", "", "File with one command per line, '-' for stdin. Empty lines and lines started with '#' are skipped")
+	flags.BoolVar(&res.raw, "raw", false, "Return telnet output as is")
+	flags.StringVar(&res.parse, "parse", "", "Template used to parse output of single command")
+	flags.BoolVar(&res.onErrorContinue, "continue", false, "Continue commands of file after error")
+	flags.StringVar(&res.stdin, "stdin", "", "File sent to input of single console command, '-' for stdin")
+	flags.BoolVar(&res.keepStdinOpen, "keep-stdin", false, "Keep input of console command open for 'cmdproxy stdin'")
+	flags.StringVar(&res.encoding, "encoding", "", "Output encoding: utf8, base64 or raw. Raw output of single command is written as is")
+	flags.Int64Var(&res.maxOutput, "max-output", 0, "Max bytes of output, longer output is truncated. Max of service if 0")
+
+	return res
+}
+
+// Request of command args or commands of file
+func (o *commandFlags) request(args []string) (model.CommandRequest, error) {
+	request := model.CommandRequest{
+		Raw:            o.raw,
+		Parse:          o.parse,
+		OutputEncoding: o.encoding,
+		MaxOutputBytes: o.maxOutput,
+	}
+	if o.onErrorContinue {
+		request.OnError = model.OnErrorContinue
+	}
+
+	command := strings.Join(args, " ")
+
+	if o.file == "" {
+		if command == "" {
+			return request, fmt.Errorf("Command is empty")
+		}
+
+		request.Command = command
+		request.KeepStdinOpen = o.keepStdinOpen
+
+		if o.stdin != "" {
+			data, err := readFile(o.stdin)
+			if err != nil {
+				return request, err
+			}
+
+			request.Stdin = base64.StdEncoding.EncodeToString(data)
+			request.StdinEncoding = model.StdinEncodingBase64
+		}
+
+		return request, nil
+	}
+
+	if o.stdin != "" || o.keepStdinOpen {
+		return request, fmt.Errorf("-stdin and -keep-stdin can not be used with -file")
+	}
+
+	if command != "" {
+		return request, fmt.Errorf("Command and -file can not be used together")
+	}
+
+	commands, err := readLines(o.file)
+	if err != nil {
+		return request, err
+	}
+	if len(commands) == 0 {
+		return request, fmt.Errorf("No commands in file: %v", o.file)
+	}
+
+	request.Commands = commands
+
+	return request, nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("cmdproxy "+name, flag.ExitOnError)
+}
+
+// Exactly count positional args are expected
+func checkArgs(flags *flag.FlagSet, count int) error {
+	if flags.NArg() != count {
+		return fmt.Errorf("%v expects %v args, got %v. See: %v -h", flags.Name(), count, flags.NArg(), flags.Name())
+	}
+
+	return nil
+}
+
+func printSession(o *app, sess model.SessionResource) error {
+	return o.print(sess, func(w io.Writer) {
+		fmt.Fprintln(w, sess.Id)
+	})
+}
+
+func runConnect(o *app, args []string) error {
+	flags := newFlagSet("connect")
+	profile := o.profile
+	addTargetFlags(flags, &profile)
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 0); err != nil {
+		return err
+	}
+
+	sess, err := o.open(profile)
+	if err != nil {
+		return err
+	}
+
+	return printSession(o, sess.SessionResource)
+}
+
+func runCommand(o *app, args []string) error {
+	flags := newFlagSet("command")
+	cmdFlags := addCommandFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return checkArgs(flags, 1)
+	}
+
+	request, err := cmdFlags.request(flags.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	return o.execute(flags.Arg(0), request)
+}
+
+// Execute request and print response. Raw output is written as is
+func (o *app) execute(sessID string, request model.CommandRequest) error {
+	if request.OutputEncoding == model.OutputEncodingRaw {
+		output, err := o.client.CommandRaw(o.ctx, sessID, request)
+		if err != nil {
+			return err
+		}
+
+		_, err = o.stdout.Write(output)
+
+		return err
+	}
+
+	response, err := o.client.Command(o.ctx, sessID, request)
+	if err != nil {
+		return err
+	}
+
+	return o.printCommand(response)
+}
+
+func runStdin(o *app, args []string) error {
+	flags := newFlagSet("stdin")
+	closeStdin := flags.Bool("close", false, "Close input after file, command reads end of file")
+	secret := flags.Bool("secret", false, "Input is password, it is masked in session recording")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 || flags.NArg() > 2 {
+		return fmt.Errorf("%v expects <session> [path], got %v args. See: %v -h", flags.Name(), flags.NArg(), flags.Name())
+	}
+
+	request := model.StdinRequest{
+		StdinEncoding: model.StdinEncodingBase64,
+		Close:         *closeStdin,
+		Secret:        *secret,
+	}
+
+	if flags.NArg() == 2 {
+		data, err := readFile(flags.Arg(1))
+		if err != nil {
+			return err
+		}
+
+		request.Stdin = base64.StdEncoding.EncodeToString(data)
+	}
+
+	return o.client.WriteStdin(o.ctx, flags.Arg(0), request)
+}
+
+func runOutput(o *app, args []string) error {
+	flags := newFlagSet("output")
+	offset := flags.Int64("offset", 0, "Offset of page in bytes")
+	limit := flags.Int64("limit", 0, "Max bytes of page. Till the end of output if 0")
+	path := flags.String("o", "", "Output file. Stdout if empty")
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 1); err != nil {
+		return err
+	}
+
+	data, err := o.client.Output(o.ctx, flags.Arg(0), *offset, *limit)
+	if err != nil {
+		return err
+	}
+
+	return writeData(o, *path, data)
+}
+
+func runDisconnect(o *app, args []string) error {
+	flags := newFlagSet("disconnect")
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 1); err != nil {
+		return err
+	}
+
+	return o.client.Disconnect(o.ctx, flags.Arg(0))
+}
+
+func runSession(o *app, args []string) error {
+	flags := newFlagSet("session")
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 1); err != nil {
+		return err
+	}
+
+	sess, err := o.client.Get(o.ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return o.print(sess, func(w io.Writer) {
+		fmt.Fprintf(w, "%v\t%v\tclosed=%v\n", sess.Id, sess.Type, sess.Closed)
+	})
+}
+
+func runSessions(o *app, args []string) error {
+	flags := newFlagSet("sessions")
+	sessType := flags.String("type", "", "console or telnet. All sessions if empty")
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 0); err != nil {
+		return err
+	}
+
+	sessions, err := o.client.List(o.ctx, *sessType)
+	if err != nil {
+		return err
+	}
+
+	return o.print(sessions, func(w io.Writer) {
+		for _, sess := range sessions {
+			fmt.Fprintf(w, "%v\t%v\tclosed=%v\n", sess.Id, sess.Type, sess.Closed)
+		}
+	})
+}
+
+// Connect to target, execute command or commands of file and disconnect
+func runExec(o *app, args []string) error {
+	flags := newFlagSet("exec")
+	profile := o.profile
+	addTargetFlags(flags, &profile)
+	cmdFlags := addCommandFlags(flags)
+	flags.Parse(args)
+
+	request, err := cmdFlags.request(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	sess, err := o.open(profile)
+	if err != nil {
+		return err
+	}
+
+	err = o.execute(sess.Id, request)
+	closeErr := sess.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+func runRecording(o *app, args []string) error {
+	flags := newFlagSet("recording")
+	path := flags.String("o", "", "Output file. Stdout if empty")
+	flags.Parse(args)
+
+	if err := checkArgs(flags, 1); err != nil {
+		return err
+	}
+
+	data, err := o.client.Recording(o.ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return writeData(o, *path, data)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/deminds/CmdProxy/client"
+)
+
+// Flags of session target. Defaults are taken from profile
+func addTargetFlags(flags *flag.FlagSet, profile *Profile) {
+	telnet := &profile.Telnet
+
+	flags.StringVar(&profile.Target, "target", profile.Target, "console or telnet. telnet if device or host is set, console otherwise")
+	flags.BoolVar(&profile.Record, "record", profile.Record, "Record session transcript")
+
+	console := &profile.Console
+	flags.Var(&optionalUint{&console.Uid}, "uid", "Run console commands as uid. Requires -gid")
+	flags.Var(&optionalUint{&console.Gid}, "gid", "Run console commands as gid. Requires -uid")
+	flags.StringVar(&console.Dir, "dir", console.Dir, "Working dir of console commands")
+	flags.Var(&envFlag{&console.Env}, "env", "NAME=VALUE environment variable of console commands. Can be repeated")
+	flags.StringVar(&console.EnvMode, "env-mode", console.EnvMode, "extend or replace service environment by -env")
+	flags.StringVar(&console.Umask, "umask", console.Umask, "Octal umask of console commands, e.g. 027")
+
+	flags.StringVar(&telnet.Device, "device", telnet.Device, "Inventory device")
+	flags.StringVar(&telnet.Host, "host", telnet.Host, "Telnet host")
+	flags.IntVar(&telnet.Port, "port", telnet.Port, "Telnet port")
+	flags.StringVar(&telnet.Login, "login", telnet.Login, "Login on device")
+	flags.StringVar(&telnet.Password, "password", telnet.Password, "Password on device")
+	flags.StringVar(&telnet.LoginExpectedString, "login-expected", telnet.LoginExpectedString, "Regexp of login prompt")
+	flags.StringVar(&telnet.PasswordExpectedString, "password-expected", telnet.PasswordExpectedString, "Regexp of password prompt")
+	flags.StringVar(&telnet.HostnameExpectedString, "hostname-expected", telnet.HostnameExpectedString, "Regexp of command prompt")
+	flags.StringVar(&telnet.ContinueCommandExpectedString, "continue-expected", telnet.ContinueCommandExpectedString, "Regexp of paging prompt, e.g. ' --More--'")
+	flags.StringVar(&telnet.ExpectedStringsMode, "expected-strings-mode", telnet.ExpectedStringsMode, "regexp or literal. Literal expected strings are matched as text")
+	flags.StringVar(&telnet.EnablePassword, "enable-password", telnet.EnablePassword, "Escalate to privileged mode with password")
+	flags.BoolVar(&telnet.Shared, "shared", telnet.Shared, "Share authenticated connection with other sessions")
+}
+
+// Target of profile. telnet if device or host is set, console otherwise
+func targetOf(profile Profile) (string, error) {
+	switch profile.Target {
+	case TargetConsole, TargetTelnet:
+		return profile.Target, nil
+	case "":
+		if profile.Telnet.Device != "" || profile.Telnet.Host != "" {
+			return TargetTelnet, nil
+		}
+
+		return TargetConsole, nil
+	default:
+		return "", fmt.Errorf("Wrong target: %v", profile.Target)
+	}
+}
+
+// Open session to target of profile
+func (o *app) open(profile Profile) (*client.Session, error) {
+	target, err := targetOf(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if target == TargetConsole {
+		return o.client.OpenConsoleWithOptions(o.ctx, profile.Record, profile.Console)
+	}
+
+	request := profile.Telnet
+	request.Record = profile.Record
+
+	return o.client.OpenTelnet(o.ctx, request)
+}
+
+// Flag of optional uint32 value
+type optionalUint struct {
+	value **uint32
+}
+
+func (o *optionalUint) String() string {
+	if o.value == nil || *o.value == nil {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(**o.value), 10)
+}
+
+func (o *optionalUint) Set(value string) error {
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return err
+	}
+
+	res := uint32(parsed)
+	*o.value = &res
+
+	return nil
+}
+
+// Repeated NAME=VALUE flag added to map
+type envFlag struct {
+	env *map[string]string
+}
+
+func (o *envFlag) String() string {
+	if o.env == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", *o.env)
+}
+
+func (o *envFlag) Set(value string) error {
+	idx := strings.Index(value, "=")
+	if idx <= 0 {
+		return fmt.Errorf("expected NAME=VALUE")
+	}
+
+	// map of profile is not changed
+	env := map[string]string{}
+	for name, val := range *o.env {
+		env[name] = val
+	}
+	env[value[:idx]] = value[idx+1:]
+	*o.env = env
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package configpush
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/deminds/CmdProxy/inventory"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/session"
+	"github.com/deminds/CmdProxy/session/types"
+	"github.com/golang/glog"
+)
+
+const (
+	// snapshot lines which are not replayed
+	SnapshotCommentPrefix = "!"
+)
+
+// Error markers of Cisco-like CLI used if profile has no config error strings.
+// Otherwise rejected line would be taken as applied
+var DefaultErrorStrings = []string{
+	`(?m)^\s*%`,
+	`Invalid input`,
+	`Incomplete command`,
+}
+
+func NewPusher(deviceInventory *inventory.Inventory, telnetFactory *types.TelnetSessionFactory) *Pusher {
+	return &Pusher{
+		inventory:     deviceInventory,
+		telnetFactory: telnetFactory,
+	}
+}
+
+// Apply config lines to inventory device in config mode and roll back on error
+type Pusher struct {
+	inventory     *inventory.Inventory
+	telnetFactory *types.TelnetSessionFactory
+}
+
+func (o *Pusher) Push(request model.ConfigPushRequest) model.ConfigPushResponse {
+	logPrefix := "Pusher.Push()"
+
+	rollback := request.Rollback
+	if rollback == "" {
+		rollback = model.RollbackNone
+	}
+
+	res := model.ConfigPushResponse{
+		Device:     request.Device,
+		Status:     model.Ok,
+		Applied:    []model.CommandResult{},
+		NotApplied: request.Lines,
+		Rollback:   rollback,
+	}
+
+	fail := func(err error) model.ConfigPushResponse {
+		glog.Errorf("%v Device: %v, Error: %v", logPrefix, request.Device, err)
+		res.Status = model.Error
+		res.Error = err.Error()
+
+		return res
+	}
+
+	device, err := o.inventory.Get(request.Device)
+	if err != nil {
+		return fail(err)
+	}
+
+	profile, err := o.inventory.GetProfile(device.Profile)
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := checkProfile(profile, rollback); err != nil {
+		return fail(err)
+	}
+
+	errorStrings := profile.ConfigErrorStrings
+	if len(errorStrings) == 0 {
+		errorStrings = DefaultErrorStrings
+	}
+
+	errorRegexps, err := compileAll(errorStrings)
+	if err != nil {
+		return fail(err)
+	}
+
+	ignoreRegexps, err := compileAll(append(append([]string{}, DefaultIgnoreStrings...), profile.ConfigIgnoreStrings...))
+	if err != nil {
+		return fail(err)
+	}
+
+	requestData, err := o.inventory.Resolve(model.ConnectTelnetRequest{Device: device.Name})
+	if err != nil {
+		return fail(err)
+	}
+	if !requestData.IsValid() {
+		return fail(fmt.Errorf("device settings are not valid. Device: %v", device.Name))
+	}
+
+	sess, err := o.telnetFactory.New(requestData)
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := sess.Connect(); err != nil {
+		return fail(err)
+	}
+
+	defer func() {
+		if !sess.IsClose() {
+			sess.Close()
+		}
+	}()
+
+	d := &deviceConfig{
+		sess:         sess,
+		profile:      profile,
+		errorRegexps: errorRegexps,
+	}
+
+	return d.push(request, rollback, ignoreRegexps, res)
+}
+
+type deviceConfig struct {
+	sess         session.ISession
+	profile      inventory.Profile
+	errorRegexps []*regexp.Regexp
+}
+
+// Save state, apply lines and roll back by rollback method if a line failed
+func (o *deviceConfig) push(
+	request model.ConfigPushRequest,
+	rollback string,
+	ignore []*regexp.Regexp,
+	res model.ConfigPushResponse) model.ConfigPushResponse {
+
+	logPrefix := "deviceConfig.push()"
+
+	fail := func(err error) model.ConfigPushResponse {
+		glog.Errorf("%v Device: %v, Error: %v", logPrefix, request.Device, err)
+		res.Status = model.Error
+		res.Error = err.Error()
+
+		return res
+	}
+
+	// state before change
+	snapshot := ""
+	var err error
+	switch rollback {
+	case model.RollbackSnapshot:
+		snapshot, err = o.run(o.profile.BackupCommand)
+	case model.RollbackNative:
+		if o.profile.CheckpointCommand != "" {
+			_, err = o.run(o.profile.CheckpointCommand)
+		}
+	}
+	if err != nil {
+		return fail(fmt.Errorf("%v Save state before change. Nothing applied. Error: %v", logPrefix, err))
+	}
+
+	applied, err := o.apply(request.Lines)
+	res.Applied = applied
+	res.NotApplied = request.Lines[len(applied):]
+	if err == nil {
+		glog.Infof("%v Applied. Device: %v, Lines: %v", logPrefix, request.Device, len(applied))
+
+		return res
+	}
+
+	// failed line is the last of applied with error status
+	res = fail(err)
+
+	switch rollback {
+	case model.RollbackSnapshot:
+		res.RollbackLines, err = o.restore(snapshot, ignore)
+	case model.RollbackNative:
+		_, err = o.run(o.profile.RollbackCommand)
+	default:
+		return res
+	}
+
+	if err != nil {
+		glog.Errorf("%v Rollback failed. Device: %v, Method: %v, Error: %v", logPrefix, request.Device, rollback, err)
+		res.RollbackError = err.Error()
+
+		return res
+	}
+
+	glog.Infof("%v Rolled back. Device: %v, Method: %v", logPrefix, request.Device, rollback)
+	res.RolledBack = true
+
+	return res
+}
+
+// Enter config mode, send lines and exit config mode. Stop on first error.
+// Return results of sent lines
+func (o *deviceConfig) apply(lines []string) ([]model.CommandResult, error) {
+	results := []model.CommandResult{}
+
+	if _, err := o.run(o.profile.ConfigCommand); err != nil {
+		return results, fmt.Errorf("deviceConfig.apply() Enter config mode. Error: %v", err)
+	}
+
+	var applyErr error
+	for _, line := range lines {
+		res := model.CommandResult{
+			Command: line,
+			Status:  model.Ok,
+		}
+
+		output, err := o.run(line)
+		res.Output = output
+		if err != nil {
+			res.Status = model.Error
+			res.Error = err.Error()
+			results = append(results, res)
+			applyErr = fmt.Errorf("deviceConfig.apply() Line: '%v', Error: %v", line, err)
+
+			break
+		}
+
+		results = append(results, res)
+	}
+
+	// leave config mode even after error
+	if o.profile.ConfigExitCommand != "" && !o.sess.IsClose() {
+		if _, err := o.run(o.profile.ConfigExitCommand); err != nil && applyErr == nil {
+			applyErr = fmt.Errorf("deviceConfig.apply() Exit config mode. Error: %v", err)
+		}
+	}
+
+	return results, applyErr
+}
+
+// Turn config back into snapshot by diff of snapshot and current config. Return sent lines
+func (o *deviceConfig) restore(snapshot string, ignore []*regexp.Regexp) ([]string, error) {
+	current, err := o.run(o.profile.BackupCommand)
+	if err != nil {
+		return nil, fmt.Errorf("deviceConfig.restore() Show current config. Error: %v", err)
+	}
+
+	lines := rollbackLines(parseConfig(snapshot, ignore), parseConfig(current, ignore))
+	if len(lines) == 0 {
+		return lines, nil
+	}
+
+	glog.Infof("deviceConfig.restore() Rollback lines: %v", lines)
+
+	_, err = o.apply(lines)
+
+	return lines, err
+}
+
+// Run command and check error markers in output
+func (o *deviceConfig) run(command string) (string, error) {
+	cmdResult, err := o.sess.Command(command, session.CommandOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	// cut snapshot would roll back lines missing from it
+	if cmdResult.Truncated {
+		return cmdResult.Output, fmt.Errorf("output is cut by read limit. Size: %v", len(cmdResult.Output))
+	}
+
+	for _, re := range o.errorRegexps {
+		if match := re.FindString(cmdResult.Output); match != "" {
+			return cmdResult.Output, fmt.Errorf("error marker '%v' in output", match)
+		}
+	}
+
+	return cmdResult.Output, nil
+}
+
+func checkProfile(profile inventory.Profile, rollback string) error {
+	if profile.ConfigCommand == "" {
+		return fmt.Errorf("checkProfile() Profile has no config command")
+	}
+
+	if rollback == model.RollbackSnapshot && profile.BackupCommand == "" {
+		return fmt.Errorf("checkProfile() Profile has no backup command for snapshot rollback")
+	}
+
+	if rollback == model.RollbackNative && profile.RollbackCommand == "" {
+		return fmt.Errorf("checkProfile() Profile has no rollback command for native rollback")
+	}
+
+	return nil
+}
+
+func compileAll(exprs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("compileAll() Wrong regexp: '%v', Error: %v", expr, err)
+		}
+
+		res = append(res, re)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,179 @@
+package configpush
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/deminds/CmdProxy/inventory"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/session"
+)
+
+const testRejectOutput = "bad command\n        ^\n% Invalid input detected at '^' marker."
+
+// Device with flat running config. Lines of reject set are refused with error marker
+type fakeConfigDevice struct {
+	config   []string
+	reject   map[string]bool
+	commands []string
+	// output of config is cut by read limit
+	truncated bool
+}
+
+func (o *fakeConfigDevice) Command(command string, options session.CommandOptions) (session.CommandResult, error) {
+	o.commands = append(o.commands, command)
+
+	switch {
+	case command == "show running-config":
+		return session.CommandResult{
+			Output:    "Building configuration...\n!\n" + strings.Join(o.config, "\n") + "\nend",
+			Truncated: o.truncated,
+		}, nil
+	case command == "configure terminal" || command == "end" || command == "exit":
+		return session.CommandResult{}, nil
+	case o.reject[command]:
+		return session.CommandResult{Output: testRejectOutput}, nil
+	case strings.HasPrefix(command, NegatePrefix):
+		for idx, line := range o.config {
+			if line == strings.TrimPrefix(command, NegatePrefix) {
+				o.config = append(o.config[:idx], o.config[idx+1:]...)
+
+				break
+			}
+		}
+	default:
+		o.config = append(o.config, command)
+	}
+
+	return session.CommandResult{}, nil
+}
+
+func (o *fakeConfigDevice) Connect() error               { return nil }
+func (o *fakeConfigDevice) Ping() bool                   { return true }
+func (o *fakeConfigDevice) GetId() string                { return "fake" }
+func (o *fakeConfigDevice) GetType() session.SessionType { return session.SessionTypeTelnet }
+func (o *fakeConfigDevice) IsClose() bool                { return false }
+func (o *fakeConfigDevice) Close()                       {}
+
+// Profile without error strings, so default error markers are used
+func newTestDeviceConfig(t *testing.T, device *fakeConfigDevice, profile inventory.Profile) *deviceConfig {
+	errorRegexps, err := compileAll(DefaultErrorStrings)
+	if err != nil {
+		t.Fatalf("compileAll(). Error: %v", err)
+	}
+
+	return &deviceConfig{
+		sess:         device,
+		profile:      profile,
+		errorRegexps: errorRegexps,
+	}
+}
+
+func testPushResponse(request model.ConfigPushRequest, rollback string) model.ConfigPushResponse {
+	return model.ConfigPushResponse{
+		Device:     request.Device,
+		Status:     model.Ok,
+		Applied:    []model.CommandResult{},
+		NotApplied: request.Lines,
+		Rollback:   rollback,
+	}
+}
+
+func TestPushRejectedLineRollsBackSnapshot(t *testing.T) {
+	device := &fakeConfigDevice{
+		config: []string{"hostname sw1", "ip domain-lookup"},
+		reject: map[string]bool{"bad command": true},
+	}
+	profile := inventory.Profile{
+		BackupCommand:     "show running-config",
+		ConfigCommand:     "configure terminal",
+		ConfigExitCommand: "end",
+	}
+	if err := checkProfile(profile, model.RollbackSnapshot); err != nil {
+		t.Fatalf("checkProfile(). Error: %v", err)
+	}
+
+	request := model.ConfigPushRequest{
+		Device: "sw1",
+		Lines:  []string{"ntp server 10.0.0.1", "no ip domain-lookup", "bad command", "snmp-server community x"},
+	}
+	d := newTestDeviceConfig(t, device, profile)
+
+	res := d.push(request, model.RollbackSnapshot, testIgnore(t), testPushResponse(request, model.RollbackSnapshot))
+
+	if res.Status != model.Error || len(res.Applied) != 3 || res.Applied[2].Status != model.Error {
+		t.Fatalf("Rejected line is not reported. Response: %+v", res)
+	}
+	if !reflect.DeepEqual(res.NotApplied, []string{"snmp-server community x"}) {
+		t.Fatalf("Wrong not applied lines: %q", res.NotApplied)
+	}
+	if !res.RolledBack || res.RollbackError != "" {
+		t.Fatalf("Config is not rolled back. Response: %+v", res)
+	}
+
+	expectedRollback := []string{"no ntp server 10.0.0.1", "ip domain-lookup"}
+	if !reflect.DeepEqual(res.RollbackLines, expectedRollback) {
+		t.Fatalf("Wrong rollback lines. Expected: %q, Actual: %q", expectedRollback, res.RollbackLines)
+	}
+	if !reflect.DeepEqual(device.config, []string{"hostname sw1", "ip domain-lookup"}) {
+		t.Fatalf("Config is not restored: %q", device.config)
+	}
+}
+
+func TestPushRejectedLineRunsNativeRollback(t *testing.T) {
+	device := &fakeConfigDevice{
+		reject: map[string]bool{"bad command": true},
+	}
+	profile := inventory.Profile{
+		ConfigCommand:     "configure terminal",
+		ConfigExitCommand: "end",
+		CheckpointCommand: "checkpoint",
+		RollbackCommand:   "rollback",
+	}
+
+	request := model.ConfigPushRequest{Device: "sw1", Lines: []string{"ntp server 10.0.0.1", "bad command"}}
+	d := newTestDeviceConfig(t, device, profile)
+
+	res := d.push(request, model.RollbackNative, nil, testPushResponse(request, model.RollbackNative))
+	if res.Status != model.Error || !res.RolledBack {
+		t.Fatalf("Config is not rolled back. Response: %+v", res)
+	}
+
+	expected := []string{"checkpoint", "configure terminal", "ntp server 10.0.0.1", "bad command", "end", "rollback"}
+	if !reflect.DeepEqual(device.commands, expected) {
+		t.Fatalf("Wrong commands. Expected: %q, Actual: %q", expected, device.commands)
+	}
+}
+
+func TestPushAppliedWithoutRollback(t *testing.T) {
+	device := &fakeConfigDevice{}
+	profile := inventory.Profile{ConfigCommand: "configure terminal", ConfigExitCommand: "end"}
+
+	request := model.ConfigPushRequest{Device: "sw1", Lines: []string{"ntp server 10.0.0.1"}}
+	d := newTestDeviceConfig(t, device, profile)
+
+	res := d.push(request, model.RollbackNone, nil, testPushResponse(request, model.RollbackNone))
+	if res.Status != model.Ok || len(res.Applied) != 1 || len(res.NotApplied) != 0 || res.RolledBack {
+		t.Fatalf("Wrong response: %+v", res)
+	}
+}
+
+func TestPushTruncatedSnapshotIsNotApplied(t *testing.T) {
+	device := &fakeConfigDevice{config: []string{"hostname sw1"}, truncated: true}
+	profile := inventory.Profile{
+		BackupCommand: "show running-config",
+		ConfigCommand: "configure terminal",
+	}
+
+	request := model.ConfigPushRequest{Device: "sw1", Lines: []string{"ntp server 10.0.0.1"}}
+	d := newTestDeviceConfig(t, device, profile)
+
+	res := d.push(request, model.RollbackSnapshot, testIgnore(t), testPushResponse(request, model.RollbackSnapshot))
+	if res.Status != model.Error || len(res.Applied) != 0 || res.RolledBack {
+		t.Fatalf("Change is applied with cut snapshot. Response: %+v", res)
+	}
+	if !reflect.DeepEqual(device.commands, []string{"show running-config"}) {
+		t.Fatalf("Wrong commands: %q", device.commands)
+	}
+}
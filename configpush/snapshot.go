@@ -0,0 +1,139 @@
+package configpush
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	NegatePrefix = "no "
+	// leave config section entered by parent line
+	SectionExitCommand = "exit"
+)
+
+// Lines of show running-config which are not config commands
+var DefaultIgnoreStrings = []string{
+	`^Building configuration`,
+	`^Current configuration\s*:`,
+	`^Last configuration change`,
+	`^NVRAM config last updated`,
+	`^version\s`,
+	`^end$`,
+}
+
+// Config line with lines of its section. Sections are defined by indentation as in Cisco IOS
+type configNode struct {
+	line     string
+	children []*configNode
+}
+
+// Parse config shown by backup command. Empty lines, comments and lines matched by ignore are skipped
+func parseConfig(config string, ignore []*regexp.Regexp) []*configNode {
+	root := &configNode{}
+	// parents by indent of their lines
+	stack := []*configNode{root}
+	indents := []int{-1}
+
+	for _, rawLine := range strings.Split(config, "\n") {
+		rawLine = strings.TrimRight(rawLine, " \r")
+		line := strings.TrimSpace(rawLine)
+		if line == "" || strings.HasPrefix(line, SnapshotCommentPrefix) || isIgnored(line, ignore) {
+			continue
+		}
+
+		indent := len(rawLine) - len(strings.TrimLeft(rawLine, " "))
+		for indent <= indents[len(indents)-1] {
+			stack = stack[:len(stack)-1]
+			indents = indents[:len(indents)-1]
+		}
+
+		node := &configNode{line: line}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, node)
+
+		stack = append(stack, node)
+		indents = append(indents, indent)
+	}
+
+	return root.children
+}
+
+func isIgnored(line string, ignore []*regexp.Regexp) bool {
+	for _, re := range ignore {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lines which turn current config back into snapshot: lines added after snapshot are negated,
+// lines removed after snapshot are added again. Changed sections are entered by parent line and left by exit
+func rollbackLines(snapshot []*configNode, current []*configNode) []string {
+	res := []string{}
+
+	before := indexNodes(snapshot)
+	after := indexNodes(current)
+
+	// removal goes first, e.g. changed "ip address" is removed before old one is set
+	negated := map[string]bool{}
+	for _, node := range current {
+		if _, exist := before[node.line]; !exist {
+			negated[negate(node.line)] = true
+			res = append(res, negate(node.line))
+		}
+	}
+
+	for _, node := range snapshot {
+		currentNode, exist := after[node.line]
+		if !exist {
+			// "no ip domain-lookup" restores itself by negation of "ip domain-lookup"
+			if !negated[node.line] || len(node.children) > 0 {
+				res = append(res, sectionLines(node)...)
+			}
+
+			continue
+		}
+
+		children := rollbackLines(node.children, currentNode.children)
+		if len(children) > 0 {
+			res = append(res, node.line)
+			res = append(res, children...)
+			res = append(res, SectionExitCommand)
+		}
+	}
+
+	return res
+}
+
+func indexNodes(nodes []*configNode) map[string]*configNode {
+	res := make(map[string]*configNode, len(nodes))
+	for _, node := range nodes {
+		res[node.line] = node
+	}
+
+	return res
+}
+
+// Line with its section
+func sectionLines(node *configNode) []string {
+	res := []string{node.line}
+	if len(node.children) == 0 {
+		return res
+	}
+
+	for _, child := range node.children {
+		res = append(res, sectionLines(child)...)
+	}
+
+	return append(res, SectionExitCommand)
+}
+
+func negate(line string) string {
+	if strings.HasPrefix(line, NegatePrefix) {
+		return strings.TrimPrefix(line, NegatePrefix)
+	}
+
+	return NegatePrefix + line
+}
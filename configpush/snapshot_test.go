@@ -0,0 +1,121 @@
+package configpush
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+const testSnapshot = `Building configuration...
+
+Current configuration : 1024 bytes
+!
+version 15.2
+hostname sw1
+!
+interface GigabitEthernet0/1
+ description uplink
+ switchport mode trunk
+!
+interface GigabitEthernet0/2
+ shutdown
+!
+router ospf 1
+ area 0 authentication
+ redistribute static
+!
+no ip domain-lookup
+!
+end
+`
+
+func testIgnore(t *testing.T) []*regexp.Regexp {
+	res, err := compileAll(DefaultIgnoreStrings)
+	if err != nil {
+		t.Fatalf("compileAll(). Error: %v", err)
+	}
+
+	return res
+}
+
+func TestParseConfigSkipsHeaderAndFooter(t *testing.T) {
+	nodes := parseConfig(testSnapshot, testIgnore(t))
+
+	lines := []string{}
+	for _, node := range nodes {
+		lines = append(lines, node.line)
+	}
+
+	expected := []string{
+		"hostname sw1",
+		"interface GigabitEthernet0/1",
+		"interface GigabitEthernet0/2",
+		"router ospf 1",
+		"no ip domain-lookup",
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Wrong lines. Expected: %q, Actual: %q", expected, lines)
+	}
+
+	if len(nodes[1].children) != 2 || nodes[1].children[1].line != "switchport mode trunk" {
+		t.Fatalf("Wrong section of interface: %+v", nodes[1].children)
+	}
+}
+
+func TestRollbackLines(t *testing.T) {
+	current := `Building configuration...
+hostname sw1-new
+!
+interface GigabitEthernet0/1
+ description changed
+ switchport mode trunk
+!
+interface GigabitEthernet0/2
+ shutdown
+!
+interface Loopback5
+ ip address 10.0.0.5 255.255.255.255
+!
+router ospf 1
+ redistribute static
+!
+ip domain-lookup
+!
+end
+`
+	ignore := testIgnore(t)
+	lines := rollbackLines(parseConfig(testSnapshot, ignore), parseConfig(current, ignore))
+
+	expected := []string{
+		"no hostname sw1-new",
+		"no interface Loopback5",
+		"no ip domain-lookup",
+		"hostname sw1",
+		"interface GigabitEthernet0/1",
+		"no description changed",
+		"description uplink",
+		"exit",
+		"router ospf 1",
+		"area 0 authentication",
+		"exit",
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Wrong rollback lines.\nExpected: %q\nActual:   %q", expected, lines)
+	}
+}
+
+func TestRollbackLinesRestoresRemovedSection(t *testing.T) {
+	ignore := testIgnore(t)
+	snapshot := parseConfig("interface Vlan10\n ip address 10.0.10.1 255.255.255.0\n no shutdown\n", ignore)
+
+	lines := rollbackLines(snapshot, parseConfig("", ignore))
+
+	expected := []string{"interface Vlan10", "ip address 10.0.10.1 255.255.255.0", "no shutdown", "exit"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Wrong rollback lines.\nExpected: %q\nActual:   %q", expected, lines)
+	}
+
+	if lines := rollbackLines(snapshot, snapshot); len(lines) != 0 {
+		t.Fatalf("Same config should have no rollback lines. Actual: %q", lines)
+	}
+}
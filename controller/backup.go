@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/golang/glog"
+)
+
+const (
+	DeviceParam      = "device"
+	VersionParam     = "version"
+	FromVersionParam = "from"
+	ToVersionParam   = "to"
+
+	ContentTypeTextPlainHeader = "text/plain; charset=utf-8"
+)
+
+// Backup configs of inventory devices on demand
+func (o *HttpController) BackupRunHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "BackupRunHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if !o.checkBackupEnabled(respWriter, logPrefix) {
+		return
+	}
+
+	if request.Method != http.MethodPost {
+		glog.Errorf("%v Wrong message type. Expected: POST. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	contentTypeHeader := request.Header.Get(ContentTypeHeader)
+	if contentTypeHeader != ContentTypeAppJsonHeader {
+		glog.Errorf("%v Content-Type should be application/json. Content-Type: %v", logPrefix, contentTypeHeader)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	msgReqBytes, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		glog.Errorf("%v Error read POST message. Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	var msgReq model.BackupRequest
+	if err := json.Unmarshal(msgReqBytes, &msgReq); err != nil {
+		glog.Errorf("%v Error unmarshal to BackupRequest. RawMsg: %s, Error: %v", logPrefix, msgReqBytes, err)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	devices, err := o.backuper.Select(msgReq)
+	if err != nil {
+		glog.Errorf("%v Select devices. Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	response := model.BackupResponse{
+		Results: o.backuper.Run(devices),
+	}
+
+	o.writeJson(respWriter, logPrefix, response)
+}
+
+func (o *HttpController) BackupVersionsHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "BackupVersionsHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if !o.checkBackupEnabled(respWriter, logPrefix) {
+		return
+	}
+
+	if request.Method != http.MethodGet {
+		glog.Errorf("%v Wrong message type. Expected: GET. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	device := request.URL.Query().Get(DeviceParam)
+
+	versions, err := o.backuper.GetStore().List(device)
+	if err != nil {
+		glog.Errorf("%v List versions. Device: %v, Error: %v", logPrefix, device, err)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	response := model.BackupVersionListResponse{
+		Device:   device,
+		Versions: versions,
+	}
+
+	o.writeJson(respWriter, logPrefix, response)
+}
+
+func (o *HttpController) BackupConfigHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "BackupConfigHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if !o.checkBackupEnabled(respWriter, logPrefix) {
+		return
+	}
+
+	if request.Method != http.MethodGet {
+		glog.Errorf("%v Wrong message type. Expected: GET. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	query := request.URL.Query()
+	device := query.Get(DeviceParam)
+	version := query.Get(VersionParam)
+
+	config, err := o.backuper.GetStore().Get(device, version)
+	if err != nil {
+		glog.Errorf("%v Get config. Device: %v, Version: %v, Error: %v", logPrefix, device, version, err)
+		respWriter.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	o.writeText(respWriter, logPrefix, config)
+}
+
+// Unified diff between two versions of device config
+func (o *HttpController) BackupDiffHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "BackupDiffHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if !o.checkBackupEnabled(respWriter, logPrefix) {
+		return
+	}
+
+	if request.Method != http.MethodGet {
+		glog.Errorf("%v Wrong message type. Expected: GET. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	query := request.URL.Query()
+	device := query.Get(DeviceParam)
+	from := query.Get(FromVersionParam)
+	to := query.Get(ToVersionParam)
+
+	diff, err := o.backuper.GetStore().Diff(device, from, to)
+	if err != nil {
+		glog.Errorf("%v Diff. Device: %v, From: %v, To: %v, Error: %v", logPrefix, device, from, to, err)
+		respWriter.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	o.writeText(respWriter, logPrefix, diff)
+}
+
+func (o *HttpController) checkBackupEnabled(respWriter http.ResponseWriter, logPrefix string) bool {
+	if o.backuper == nil {
+		glog.Errorf("%v Backup is disabled. Set -backup-dir to enable it", logPrefix)
+		respWriter.WriteHeader(http.StatusNotImplemented)
+
+		return false
+	}
+
+	return true
+}
+
+func (o *HttpController) writeText(respWriter http.ResponseWriter, logPrefix string, text string) {
+	respWriter.Header().Set(ContentTypeHeader, ContentTypeTextPlainHeader)
+	respWriter.WriteHeader(http.StatusOK)
+	if _, err := respWriter.Write([]byte(text)); err != nil {
+		glog.Errorf("%v Error write response. Error: %v", logPrefix, err)
+	}
+}
@@ -0,0 +1,280 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/session"
+	"github.com/golang/glog"
+)
+
+const (
+	ContentTypeAppNdjsonHeader = "application/x-ndjson"
+)
+
+func (o *HttpController) TelnetBroadcastHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "TelnetBroadcastHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if request.Method != http.MethodPost {
+		glog.Errorf("%v Wrong message type. Expected: POST. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	contentTypeHeader := request.Header.Get(ContentTypeHeader)
+	if contentTypeHeader != ContentTypeAppJsonHeader {
+		glog.Errorf("%v Content-Type should be application/json. Content-Type: %v", logPrefix, contentTypeHeader)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	msgReqBytes, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		glog.Errorf("%v Error read POST message. Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	var msgReq model.BroadcastRequest
+	if err := json.Unmarshal(msgReqBytes, &msgReq); err != nil {
+		glog.Errorf("%v Error unmarshal to BroadcastRequest. Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	targetErrors := o.resolveBroadcastTargets(&msgReq)
+
+	if !msgReq.IsValid() {
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	// request can only lower service limit
+	concurrency := o.broadcastConcurrency
+	if msgReq.Concurrency > 0 && msgReq.Concurrency < concurrency {
+		concurrency = msgReq.Concurrency
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	glog.Infof("%v Targets: %v, Commands: %v, Concurrency: %v, Stream: %v",
+		logPrefix, len(msgReq.Targets), msgReq.Commands, concurrency, msgReq.Stream)
+
+	results := make(chan model.BroadcastDeviceResult)
+	go o.broadcast(request.Context(), msgReq, targetErrors, concurrency, results)
+
+	if msgReq.Stream {
+		o.streamBroadcast(respWriter, results)
+
+		return
+	}
+
+	response := model.BroadcastResponse{
+		Results: make([]model.BroadcastDeviceResult, len(msgReq.Targets)),
+	}
+	for res := range results {
+		response.Results[res.Index] = res
+	}
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		glog.Errorf("%v Error marshal BroadcastResponse to json. Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	respWriter.Header().Set(ContentTypeHeader, ContentTypeAppJsonHeader)
+	respWriter.WriteHeader(http.StatusOK)
+	respWriter.Write(responseBytes)
+}
+
+// Write result of each device as json line. Results are drained even if client is gone
+func (o *HttpController) streamBroadcast(respWriter http.ResponseWriter, results <-chan model.BroadcastDeviceResult) {
+	logPrefix := "streamBroadcast()"
+
+	respWriter.Header().Set(ContentTypeHeader, ContentTypeAppNdjsonHeader)
+	respWriter.WriteHeader(http.StatusOK)
+
+	flusher, _ := respWriter.(http.Flusher)
+
+	failed := false
+	for res := range results {
+		if failed {
+			continue
+		}
+
+		resBytes, err := json.Marshal(res)
+		if err != nil {
+			glog.Errorf("%v Error marshal BroadcastDeviceResult to json. Index: %v, Error: %v", logPrefix, res.Index, err)
+
+			continue
+		}
+
+		if _, err := respWriter.Write(append(resBytes, '\n')); err != nil {
+			glog.Errorf("%v Error write result. Drop stream. Error: %v", logPrefix, err)
+			failed = true
+
+			continue
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// Add inventory devices selected by name, tags and groups to targets and resolve targets of inventory devices.
+// Return error of every target, nil if target is valid. Unknown device or wrong target fails only its result
+func (o *HttpController) resolveBroadcastTargets(msgReq *model.BroadcastRequest) []error {
+	// device selected several times is processed once
+	selected := map[string]bool{}
+	for _, name := range msgReq.Devices {
+		if !selected[name] {
+			selected[name] = true
+			msgReq.Targets = append(msgReq.Targets, model.ConnectTelnetRequest{Device: name})
+		}
+	}
+
+	if len(msgReq.Tags) > 0 || len(msgReq.Groups) > 0 {
+		for _, device := range o.inventory.List(msgReq.Tags, msgReq.Groups) {
+			if !selected[device.Name] {
+				selected[device.Name] = true
+				msgReq.Targets = append(msgReq.Targets, model.ConnectTelnetRequest{Device: device.Name})
+			}
+		}
+	}
+
+	targetErrors := make([]error, len(msgReq.Targets))
+	for idx := range msgReq.Targets {
+		target, err := o.resolveTelnetRequest(msgReq.Targets[idx])
+		if err != nil {
+			glog.Errorf("resolveBroadcastTargets() Resolve target. Index: %v, Device: %v, Error: %v", idx, msgReq.Targets[idx].Device, err)
+			targetErrors[idx] = err
+
+			continue
+		}
+
+		// credentials of target are not logged, see ConnectTelnetRequest.IsValid()
+		if !target.IsValid() {
+			glog.Errorf("resolveBroadcastTargets() Target is not valid. Index: %v, Device: %v, Host: %v", idx, target.Device, target.Host)
+			targetErrors[idx] = fmt.Errorf("target is not valid. Index: %v", idx)
+		}
+
+		msgReq.Targets[idx] = target
+	}
+
+	return targetErrors
+}
+
+// Run commands on targets with bounded concurrency. Close results when all targets are done.
+// Targets are not started after ctx is done, e.g. client is gone
+func (o *HttpController) broadcast(ctx context.Context, msgReq model.BroadcastRequest, targetErrors []error, concurrency int, results chan<- model.BroadcastDeviceResult) {
+	logPrefix := "broadcast()"
+
+	semaphore := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+
+	defer func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for idx, target := range msgReq.Targets {
+		if targetErrors[idx] != nil {
+			results <- model.BroadcastDeviceResult{
+				Index:    idx,
+				Device:   target.Device,
+				Host:     target.Host,
+				Port:     target.Port,
+				Status:   model.Error,
+				Error:    targetErrors[idx].Error(),
+				Commands: []model.CommandResult{},
+			}
+
+			continue
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			glog.Errorf("%v Request is canceled. Skip %v targets. Error: %v", logPrefix, len(msgReq.Targets)-idx, ctx.Err())
+
+			return
+		}
+		wg.Add(1)
+
+		go func(idx int, target model.ConnectTelnetRequest) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			results <- o.broadcastDevice(ctx, idx, target, msgReq.Commands, msgReq.Raw)
+		}(idx, target)
+	}
+}
+
+// Error of one device is reported in its result and does not fail the batch
+func (o *HttpController) broadcastDevice(ctx context.Context, idx int, target model.ConnectTelnetRequest, commands []string, raw bool) model.BroadcastDeviceResult {
+	logPrefix := "broadcastDevice()"
+
+	res := model.BroadcastDeviceResult{
+		Index:    idx,
+		Device:   target.Device,
+		Host:     target.Host,
+		Port:     target.Port,
+		Status:   model.Ok,
+		Commands: []model.CommandResult{},
+	}
+
+	sess, err := o.telnetFactory.New(target)
+	if err != nil {
+		glog.Errorf("%v Create telnet session. Host: %v, Error: %v", logPrefix, target.Host, err)
+		res.Status = model.Error
+		res.Error = err.Error()
+
+		return res
+	}
+
+	if err := sess.Connect(); err != nil {
+		glog.Errorf("%v sess.Connect(). Host: %v, Error: %v", logPrefix, target.Host, err)
+		res.Status = model.Error
+		res.Error = err.Error()
+
+		return res
+	}
+
+	defer func() {
+		if !sess.IsClose() {
+			sess.Close()
+		}
+	}()
+
+	for _, command := range commands {
+		cmdResult, err := sess.Command(command, session.CommandOptions{Raw: raw, Cancel: ctx.Done()})
+		if err != nil {
+			glog.Errorf("%v sess.Command(). Host: %v, Command: %v, Error: %v", logPrefix, target.Host, command, err)
+			res.Status = model.Error
+			res.Error = err.Error()
+
+			return res
+		}
+
+		res.Commands = append(res.Commands, o.commandResult(command, cmdResult, model.OutputEncodingUtf8, 0))
+	}
+
+	return res
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/golang/glog"
+)
+
+// Apply config lines to inventory device. Report is returned with status OK even if push failed
+func (o *HttpController) ConfigPushHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "ConfigPushHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if request.Method != http.MethodPost {
+		glog.Errorf("%v Wrong message type. Expected: POST. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	contentTypeHeader := request.Header.Get(ContentTypeHeader)
+	if contentTypeHeader != ContentTypeAppJsonHeader {
+		glog.Errorf("%v Content-Type should be application/json. Content-Type: %v", logPrefix, contentTypeHeader)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	msgReqBytes, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		glog.Errorf("%v Error read POST message. Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	var msgReq model.ConfigPushRequest
+	if err := json.Unmarshal(msgReqBytes, &msgReq); err != nil {
+		glog.Errorf("%v Error unmarshal to ConfigPushRequest. RawMsg: %s, Error: %v", logPrefix, msgReqBytes, err)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if !msgReq.IsValid() {
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	o.writeJson(respWriter, logPrefix, o.pusher.Push(msgReq))
+}
@@ -7,21 +7,56 @@ import (
 	"github.com/golang/glog"
 
 	"github.com/deminds/CmdProxy/model"
-	"github.com/deminds/CmdProxy/session/types"
+	"github.com/deminds/CmdProxy/session"
 )
 
 func (o *HttpController) ConsoleConnectHandler(respWriter http.ResponseWriter, request *http.Request) {
 	logPrefix := "ConsoleConnectHandler()"
 	glog.Info("%v Handle url: %v", logPrefix, request.URL.Path)
 
-	if request.Method != http.MethodGet {
-		glog.Errorf("%v Wrong message type. Expected: GET. Actual: %v", logPrefix, request.Method)
+	// GET connects with default process settings, POST accepts ConnectConsoleRequest
+	var msgReq model.ConnectConsoleRequest
+	switch request.Method {
+	case http.MethodGet:
+		msgReq.Record = request.URL.Query().Get(RecordParam) == "true"
+
+	case http.MethodPost:
+		if err := o.readJson(request, &msgReq); err != nil {
+			glog.Errorf("%v Error: %v", logPrefix, err)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+		glog.Infof("%v Received POST: %+v", logPrefix, msgReq)
+
+	default:
+		glog.Errorf("%v Wrong message type. Expected: GET, POST. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if !msgReq.IsValid() {
 		respWriter.WriteHeader(http.StatusBadRequest)
 
 		return
 	}
 
-	sess, err := types.NewConsoleSession(o.idGenerator, o.timeoutSec)
+	if err := o.consoleFactory.Validate(msgReq.ConsoleOptions); err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if err := o.consoleFactory.Authorize(msgReq.ConsoleOptions); err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	sess, err := o.connectConsole(msgReq)
 	if err != nil {
 		glog.Errorf("%v Error create local console connection. Error: %v", logPrefix, err)
 		respWriter.WriteHeader(http.StatusInternalServerError)
@@ -29,10 +64,6 @@ func (o *HttpController) ConsoleConnectHandler(respWriter http.ResponseWriter, r
 		return
 	}
 
-	sess.Connect()
-
-	o.sessionPool.Put(sess)
-
 	response := model.ConnectResponse{
 		SessionId: sess.GetId(),
 	}
@@ -53,3 +84,17 @@ func (o *HttpController) ConsoleListHandler(respWriter http.ResponseWriter, requ
 	logPrefix := "ConsoleListHandler()"
 	glog.Info("%v Handle url: %v", logPrefix, request.URL.Path)
 }
+
+// Create console session, connect and put it to pool
+func (o *HttpController) connectConsole(msgReq model.ConnectConsoleRequest) (session.ISession, error) {
+	sess, err := o.consoleFactory.New(msgReq)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.Connect()
+
+	o.sessionPool.Put(sess)
+
+	return sess, nil
+}
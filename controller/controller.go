@@ -1,15 +1,27 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"regexp"
+	"time"
 
 	"github.com/golang/glog"
 
+	"github.com/deminds/CmdProxy/backup"
+	"github.com/deminds/CmdProxy/configpush"
 	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/inventory"
 	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/outputs"
+	"github.com/deminds/CmdProxy/parser"
+	"github.com/deminds/CmdProxy/recording"
+	"github.com/deminds/CmdProxy/scheduler"
 	"github.com/deminds/CmdProxy/session"
+	"github.com/deminds/CmdProxy/session/types"
+	"github.com/deminds/CmdProxy/webhook"
 )
 
 const (
@@ -21,21 +33,89 @@ const (
 func NewHttpController(
 	pool *session.SessionPool,
 	idGenerator *generatorid.IDGenerator,
-	timeoutSec int) *HttpController {
+	timeoutSec int,
+	consoleFactory *types.ConsoleSessionFactory,
+	telnetFactory *types.TelnetSessionFactory,
+	broadcastConcurrency int,
+	inventory *inventory.Inventory,
+	backuper *backup.Backuper,
+	pusher *configpush.Pusher,
+	recordings *recording.Store,
+	jobScheduler *scheduler.Scheduler,
+	dispatcher *webhook.Dispatcher,
+	templates *parser.Registry,
+	outputStore *outputs.Store,
+	maxOutputBytes int64) *HttpController {
 
 	return &HttpController{
-		sessionPool: pool,
-		idGenerator: idGenerator,
-
-		timeoutSec: timeoutSec,
+		sessionPool:    pool,
+		idGenerator:    idGenerator,
+		consoleFactory: consoleFactory,
+		telnetFactory:  telnetFactory,
+		inventory:      inventory,
+		backuper:       backuper,
+		pusher:         pusher,
+		recordings:     recordings,
+		scheduler:      jobScheduler,
+		dispatcher:     dispatcher,
+		templates:      templates,
+		outputs:        outputStore,
+
+		timeoutSec:           timeoutSec,
+		broadcastConcurrency: broadcastConcurrency,
+		maxOutputBytes:       maxOutputBytes,
 	}
 }
 
 type HttpController struct {
-	sessionPool *session.SessionPool
-	idGenerator *generatorid.IDGenerator
+	sessionPool    *session.SessionPool
+	idGenerator    *generatorid.IDGenerator
+	consoleFactory *types.ConsoleSessionFactory
+	telnetFactory  *types.TelnetSessionFactory
+	inventory      *inventory.Inventory
+	// nil if backup is disabled
+	backuper *backup.Backuper
+	pusher   *configpush.Pusher
+	// nil if recording is disabled
+	recordings *recording.Store
+	scheduler  *scheduler.Scheduler
+	dispatcher *webhook.Dispatcher
+	templates  *parser.Registry
+	// nil if whole outputs of truncated results are not kept
+	outputs *outputs.Store
 
 	timeoutSec int
+	// max devices processed at the same time by broadcast
+	broadcastConcurrency int
+	// max bytes of command output in response. 0 - unlimited
+	maxOutputBytes int64
+}
+
+// Marshal response to json and write it with status OK
+func (o *HttpController) writeJson(respWriter http.ResponseWriter, logPrefix string, response interface{}) {
+	o.writeJsonStatus(respWriter, logPrefix, http.StatusOK, response)
+}
+
+// Marshal response to json and write it with status
+func (o *HttpController) writeJsonStatus(respWriter http.ResponseWriter, logPrefix string, status int, response interface{}) {
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		glog.Errorf("%v Error marshal response to json. Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	respWriter.Header().Set(ContentTypeHeader, ContentTypeAppJsonHeader)
+	respWriter.WriteHeader(status)
+	if _, err := respWriter.Write(responseBytes); err != nil {
+		glog.Errorf("%v Error write response. Error: %v", logPrefix, err)
+	}
+}
+
+// Write ErrorResponse with status
+func (o *HttpController) writeError(respWriter http.ResponseWriter, logPrefix string, status int, err error) {
+	o.writeJsonStatus(respWriter, logPrefix, status, model.ErrorResponse{Error: err.Error()})
 }
 
 func (o *HttpController) DisconnectHandler(respWriter http.ResponseWriter, request *http.Request) {
@@ -97,14 +177,21 @@ func (o *HttpController) CommandHandler(respWriter http.ResponseWriter, request
 
 	var msgReq model.CommandRequest
 	if err := json.Unmarshal(msgReqBytes, &msgReq); err != nil {
-		glog.Errorf("%v Error unmarshal to CommandRequest. RawMsg: %s, Error: %v", logPrefix, msgReqBytes, err)
+		glog.Errorf("%v Error unmarshal to CommandRequest. Size: %v, Error: %v", logPrefix, len(msgReqBytes), err)
 		respWriter.WriteHeader(http.StatusInternalServerError)
 
 		return
 	}
 
-	if msgReq.SessionId == "" || msgReq.Command == "" {
-		glog.Errorf("%v Received not valid message. Msg: %+v", logPrefix, msgReq)
+	if !msgReq.IsValid() {
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	template, err := o.commandTemplate(msgReq)
+	if err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
 		respWriter.WriteHeader(http.StatusBadRequest)
 
 		return
@@ -119,7 +206,7 @@ func (o *HttpController) CommandHandler(respWriter http.ResponseWriter, request
 		return
 	}
 
-	cmdOutput, err := sess.Command(msgReq.Command)
+	msgResp, err := o.executeCommand(request.Context(), sess, msgReq, template)
 	if err != nil {
 		glog.Errorf("%v Error execute command. "+
 			"ID: %v, Type: %v, CommandID: %v, Command: %v, Error: %v",
@@ -129,13 +216,10 @@ func (o *HttpController) CommandHandler(respWriter http.ResponseWriter, request
 		return
 	}
 
-	msgResp := model.CommandResponse{
-		Output: cmdOutput,
-		CommandRequest: model.CommandRequest{
-			Command:   msgReq.Command,
-			CommandId: msgReq.CommandId,
-			SessionId: sess.GetId(),
-		},
+	if msgReq.OutputEncoding == model.OutputEncodingRaw {
+		o.writeRaw(respWriter, logPrefix, msgResp)
+
+		return
 	}
 
 	msgRespByte, err := json.Marshal(msgResp)
@@ -155,3 +239,139 @@ func (o *HttpController) CommandHandler(respWriter http.ResponseWriter, request
 		return
 	}
 }
+
+// Template of Parse. nil if Parse is empty
+func (o *HttpController) commandTemplate(msgReq model.CommandRequest) (*parser.Template, error) {
+	if msgReq.Parse == "" {
+		return nil, nil
+	}
+
+	return o.templates.Get(msgReq.Parse)
+}
+
+// Execute Command or Commands of request. Error is returned only if single Command failed.
+// Commands are canceled when ctx is done
+func (o *HttpController) executeCommand(ctx context.Context, sess session.ISession, msgReq model.CommandRequest, template *parser.Template) (model.CommandResponse, error) {
+	msgResp := model.CommandResponse{
+		CommandRequest: msgReq.WithoutStdin(),
+	}
+	msgResp.SessionId = sess.GetId()
+
+	if len(msgReq.Commands) > 0 {
+		msgResp.Results = o.batchCommand(ctx, sess, msgReq)
+
+		return msgResp, nil
+	}
+
+	options := session.CommandOptions{
+		Raw:           msgReq.Raw,
+		Expect:        expectSteps(msgReq.Expect),
+		Cancel:        ctx.Done(),
+		Stdin:         msgReq.StdinBytes(),
+		KeepStdinOpen: msgReq.KeepStdinOpen,
+	}
+
+	cmdResult, err := sess.Command(msgReq.Command, options)
+	if err != nil {
+		return msgResp, err
+	}
+
+	// whole output is parsed
+	msgResp.Parsed, msgResp.ParseError = parseOutput(template, sess, textOf(cmdResult.Output, cmdResult))
+
+	output, truncated := o.limitOutput(cmdResult, msgReq.OutputEncoding, msgReq.MaxOutputBytes)
+	msgResp.Output, msgResp.Replacements = encodeOutput(output, msgReq.OutputEncoding)
+	msgResp.TruncatedOutput = truncated
+	msgResp.Prompt = textOf(cmdResult.Prompt, cmdResult)
+	msgResp.Mode = string(cmdResult.Mode)
+	msgResp.Exit = cmdResult.Exit
+
+	return msgResp, nil
+}
+
+// Execute commands in order. Stop on first error unless OnError is "continue"
+func (o *HttpController) batchCommand(ctx context.Context, sess session.ISession, msgReq model.CommandRequest) []model.CommandResult {
+	results := make([]model.CommandResult, 0, len(msgReq.Commands))
+
+	o.eachCommand(ctx, sess, msgReq, nil, func(res model.CommandResult) error {
+		results = append(results, res)
+
+		return nil
+	})
+
+	return results
+}
+
+// Execute commands in order and pass every result to handle. Output of running command
+// is passed to outputChunk if it is set. Stop on first error unless OnError is "continue" or if handle fails
+func (o *HttpController) eachCommand(ctx context.Context, sess session.ISession, msgReq model.CommandRequest,
+	outputChunk func(command string, chunk []byte), handle func(model.CommandResult) error) error {
+
+	logPrefix := "eachCommand()"
+
+	for _, command := range msgReq.Commands {
+		options := session.CommandOptions{Raw: msgReq.Raw, Cancel: ctx.Done()}
+		if outputChunk != nil {
+			command := command
+			options.OutputChunk = func(chunk []byte) {
+				outputChunk(command, chunk)
+			}
+		}
+
+		cmdResult, err := sess.Command(command, options)
+
+		res := o.commandResult(command, cmdResult, msgReq.OutputEncoding, msgReq.MaxOutputBytes)
+		if err != nil {
+			glog.Errorf("%v Error execute command. "+
+				"ID: %v, Type: %v, CommandID: %v, Command: %v, Error: %v",
+				logPrefix, sess.GetId(), sess.GetType(), msgReq.CommandId, command, err)
+
+			res = model.CommandResult{
+				Command: command,
+				Status:  model.Error,
+				Error:   err.Error(),
+				Exit:    cmdResult.Exit,
+			}
+		}
+
+		if err := handle(res); err != nil {
+			return err
+		}
+
+		if res.Status == model.Error && msgReq.OnError != model.OnErrorContinue {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Return rows and error message. Nothing is returned if template is nil
+func parseOutput(template *parser.Template, sess session.ISession, output string) ([]map[string]interface{}, string) {
+	if template == nil {
+		return nil, ""
+	}
+
+	parsed, err := template.Parse(output)
+	if err != nil {
+		glog.Errorf("parseOutput() Error parse output. ID: %v, Template: %v, Error: %v", sess.GetId(), template.GetName(), err)
+
+		return nil, err.Error()
+	}
+
+	return parsed, ""
+}
+
+// Steps are validated by CommandRequest.IsValid()
+func expectSteps(steps []model.ExpectStep) []session.ExpectStep {
+	res := make([]session.ExpectStep, 0, len(steps))
+	for _, step := range steps {
+		res = append(res, session.ExpectStep{
+			Expect:  regexp.MustCompile(step.Expect),
+			Send:    step.Send,
+			Timeout: time.Duration(step.TimeoutSec) * time.Second,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,455 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/golang/glog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/deminds/CmdProxy/grpcapi"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/parser"
+	"github.com/deminds/CmdProxy/session"
+)
+
+// gRPC API. Uses the same session pool and components as HttpController
+func NewGrpcController(httpController *HttpController) *GrpcController {
+	return &GrpcController{
+		http: httpController,
+	}
+}
+
+type GrpcController struct {
+	grpcapi.UnimplementedCmdProxyServer
+
+	http *HttpController
+}
+
+func (o *GrpcController) ConnectConsole(ctx context.Context, request *grpcapi.ConnectConsoleRequest) (*grpcapi.ConnectResponse, error) {
+	logPrefix := "GrpcController.ConnectConsole()"
+
+	msgReq := toConnectConsoleRequest(request)
+	if !msgReq.IsValid() {
+		return nil, status.Error(codes.InvalidArgument, "connect request is not valid")
+	}
+
+	if err := o.http.consoleFactory.Validate(msgReq.ConsoleOptions); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := o.http.consoleFactory.Authorize(msgReq.ConsoleOptions); err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	sess, err := o.http.connectConsole(msgReq)
+	if err != nil {
+		glog.Errorf("%v Error create local console connection. Error: %v", logPrefix, err)
+
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &grpcapi.ConnectResponse{SessionId: sess.GetId()}, nil
+}
+
+func (o *GrpcController) ConnectTelnet(ctx context.Context, request *grpcapi.ConnectTelnetRequest) (*grpcapi.ConnectResponse, error) {
+	logPrefix := "GrpcController.ConnectTelnet()"
+
+	msgReq, err := o.http.resolveTelnetRequest(toConnectTelnetRequest(request))
+	if err != nil {
+		glog.Errorf("%v Resolve inventory device. Device: %v, Error: %v", logPrefix, request.GetDevice(), err)
+
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if !msgReq.IsValid() {
+		return nil, status.Error(codes.InvalidArgument, "connect request is not valid")
+	}
+
+	sess, err := o.http.connectTelnet(msgReq)
+	if err != nil {
+		glog.Errorf("%v Connect telnet session. Shared: %v, Error: %v", logPrefix, msgReq.Shared, err)
+
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &grpcapi.ConnectResponse{SessionId: sess.GetId()}, nil
+}
+
+func (o *GrpcController) Command(ctx context.Context, request *grpcapi.CommandRequest) (*grpcapi.CommandResponse, error) {
+	msgReq := toCommandRequest(request)
+
+	sess, template, err := o.prepareCommand(msgReq)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &grpcapi.CommandResponse{
+		SessionId: sess.GetId(),
+		CommandId: request.GetCommandId(),
+	}
+
+	if len(msgReq.Commands) > 0 {
+		for _, res := range o.http.batchCommand(ctx, sess, msgReq) {
+			response.Results = append(response.Results, toProtoResult(res, nil, ""))
+		}
+
+		return response, nil
+	}
+
+	response.Result, err = o.singleCommand(ctx, sess, msgReq, template, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Result of every command is sent when command is finished. If stream_output is set,
+// output of running command is sent before its result in messages with output_chunk
+func (o *GrpcController) Exec(request *grpcapi.CommandRequest, stream grpc.ServerStreamingServer[grpcapi.CommandResult]) error {
+	msgReq := toCommandRequest(request)
+
+	sess, template, err := o.prepareCommand(msgReq)
+	if err != nil {
+		return err
+	}
+
+	sender := &execSender{stream: stream}
+	defer sender.stop()
+
+	var outputChunk func(command string, chunk []byte)
+	if request.GetStreamOutput() {
+		outputChunk = sender.sendChunk
+	}
+
+	if len(msgReq.Commands) > 0 {
+		return o.http.eachCommand(stream.Context(), sess, msgReq, outputChunk, func(res model.CommandResult) error {
+			return sender.send(toProtoResult(res, nil, ""))
+		})
+	}
+
+	var singleChunk func([]byte)
+	if outputChunk != nil {
+		singleChunk = func(chunk []byte) {
+			outputChunk(msgReq.Command, chunk)
+		}
+	}
+
+	result, err := o.singleCommand(stream.Context(), sess, msgReq, template, singleChunk)
+	if err != nil {
+		return err
+	}
+
+	return sender.send(result)
+}
+
+// Messages of Exec. Chunks are sent from session routines, so sends are serialized.
+// Chunks received after Exec returns are dropped
+type execSender struct {
+	stream grpc.ServerStreamingServer[grpcapi.CommandResult]
+
+	mutex   sync.Mutex
+	stopped bool
+	// failed send of chunk, the rest of chunks is dropped
+	chunkErr error
+}
+
+func (o *execSender) send(result *grpcapi.CommandResult) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.stream.Send(result)
+}
+
+func (o *execSender) sendChunk(command string, chunk []byte) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.stopped || o.chunkErr != nil {
+		return
+	}
+
+	// chunk is reused by session after call
+	o.chunkErr = o.stream.Send(&grpcapi.CommandResult{
+		Command:     command,
+		OutputChunk: append([]byte{}, chunk...),
+	})
+	if o.chunkErr != nil {
+		glog.Errorf("execSender.sendChunk() Drop output chunks. Command: %v, Error: %v", command, o.chunkErr)
+	}
+}
+
+func (o *execSender) stop() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.stopped = true
+}
+
+func (o *GrpcController) WriteStdin(ctx context.Context, request *grpcapi.StdinRequest) (*grpcapi.StdinResponse, error) {
+	logPrefix := "GrpcController.WriteStdin()"
+
+	msgReq := model.StdinRequest{
+		SessionId:     request.GetSessionId(),
+		Stdin:         base64.StdEncoding.EncodeToString(request.GetStdin()),
+		StdinEncoding: model.StdinEncodingBase64,
+		Close:         request.GetClose(),
+		Secret:        request.GetSecret(),
+	}
+
+	httpStatus, err := o.http.writeStdin(msgReq)
+	if err != nil {
+		glog.Errorf("%v Error write stdin. ID: %v, Error: %v", logPrefix, msgReq.SessionId, err)
+
+		switch httpStatus {
+		case http.StatusNotFound:
+			return nil, status.Error(codes.NotFound, err.Error())
+		case http.StatusConflict:
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	return &grpcapi.StdinResponse{}, nil
+}
+
+func (o *GrpcController) ReadOutput(ctx context.Context, request *grpcapi.ReadOutputRequest) (*grpcapi.ReadOutputResponse, error) {
+	logPrefix := "GrpcController.ReadOutput()"
+
+	if o.http.outputs == nil {
+		return nil, status.Error(codes.NotFound, "output store is disabled")
+	}
+
+	data, size, err := o.http.outputs.Read(request.GetOutputId(), request.GetOffset(), request.GetLimit())
+	if err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+
+		// size is not known if output is not found
+		if size == 0 {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		return nil, status.Error(codes.OutOfRange, err.Error())
+	}
+
+	return &grpcapi.ReadOutputResponse{Data: data, Size: size}, nil
+}
+
+func (o *GrpcController) Disconnect(ctx context.Context, request *grpcapi.DisconnectRequest) (*grpcapi.DisconnectResponse, error) {
+	logPrefix := "GrpcController.Disconnect()"
+
+	if err := o.http.sessionPool.RemoveAndClose(request.GetSessionId()); err != nil {
+		glog.Errorf("%v Error remove connection from sessionPool. "+
+			"ID: %v, Error: %v", logPrefix, request.GetSessionId(), err)
+
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &grpcapi.DisconnectResponse{}, nil
+}
+
+func (o *GrpcController) List(ctx context.Context, request *grpcapi.ListRequest) (*grpcapi.ListResponse, error) {
+	response := &grpcapi.ListResponse{}
+
+	for _, sess := range o.http.sessionPool.List(session.SessionType(request.GetType())) {
+		response.Sessions = append(response.Sessions, &grpcapi.Session{
+			SessionId: sess.GetId(),
+			Type:      string(sess.GetType()),
+		})
+	}
+
+	return response, nil
+}
+
+// Validate request, find template and session
+func (o *GrpcController) prepareCommand(msgReq model.CommandRequest) (session.ISession, *parser.Template, error) {
+	logPrefix := "GrpcController.prepareCommand()"
+
+	if !msgReq.IsValid() {
+		return nil, nil, status.Error(codes.InvalidArgument, "command request is not valid")
+	}
+
+	template, err := o.http.commandTemplate(msgReq)
+	if err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sess, err := o.http.sessionPool.Get(msgReq.SessionId)
+	if err != nil {
+		glog.Errorf("%v SessionPool.Get(%v). Error: %v", logPrefix, msgReq.SessionId, err)
+
+		return nil, nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return sess, template, nil
+}
+
+// Output of running command is passed to outputChunk if it is set
+func (o *GrpcController) singleCommand(ctx context.Context, sess session.ISession, msgReq model.CommandRequest, template *parser.Template, outputChunk func([]byte)) (*grpcapi.CommandResult, error) {
+	logPrefix := "GrpcController.singleCommand()"
+
+	options := session.CommandOptions{
+		Raw:           msgReq.Raw,
+		Expect:        expectSteps(msgReq.Expect),
+		Cancel:        ctx.Done(),
+		Stdin:         msgReq.StdinBytes(),
+		KeepStdinOpen: msgReq.KeepStdinOpen,
+		OutputChunk:   outputChunk,
+	}
+
+	cmdResult, err := sess.Command(msgReq.Command, options)
+	if err != nil {
+		glog.Errorf("%v Error execute command. "+
+			"ID: %v, Type: %v, CommandID: %v, Command: %v, Error: %v",
+			logPrefix, sess.GetId(), sess.GetType(), msgReq.CommandId, msgReq.Command, err)
+
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	parsed, parseError := parseOutput(template, sess, textOf(cmdResult.Output, cmdResult))
+
+	result := toProtoResult(o.http.commandResult(msgReq.Command, cmdResult, msgReq.OutputEncoding, msgReq.MaxOutputBytes), parsed, parseError)
+
+	// string of proto should be valid utf-8
+	if msgReq.OutputEncoding == model.OutputEncodingRaw {
+		result.OutputBytes = []byte(result.Output)
+		result.Output = ""
+	}
+
+	return result, nil
+}
+
+func toConnectConsoleRequest(request *grpcapi.ConnectConsoleRequest) model.ConnectConsoleRequest {
+	return model.ConnectConsoleRequest{
+		Record: request.GetRecord(),
+		ConsoleOptions: model.ConsoleOptions{
+			Uid:     request.Uid,
+			Gid:     request.Gid,
+			Dir:     request.GetDir(),
+			Env:     request.GetEnv(),
+			EnvMode: request.GetEnvMode(),
+			Umask:   request.GetUmask(),
+		},
+	}
+}
+
+func toConnectTelnetRequest(request *grpcapi.ConnectTelnetRequest) model.ConnectTelnetRequest {
+	return model.ConnectTelnetRequest{
+		Device:                        request.GetDevice(),
+		Host:                          request.GetHost(),
+		Port:                          int(request.GetPort()),
+		Login:                         request.GetLogin(),
+		Password:                      request.GetPassword(),
+		LoginExpectedString:           request.GetLoginExpectedString(),
+		PasswordExpectedString:        request.GetPasswordExpectedString(),
+		HostnameExpectedString:        request.GetHostnameExpectedString(),
+		ContinueCommandExpectedString: request.GetContinueCommandExpectedString(),
+		ExpectedStringsMode:           request.GetExpectedStringsMode(),
+		TerminalType:                  request.GetTerminalType(),
+		TerminalWidth:                 int(request.GetTerminalWidth()),
+		TerminalHeight:                int(request.GetTerminalHeight()),
+		Shared:                        request.GetShared(),
+		MaxConnections:                int(request.GetMaxConnections()),
+		Record:                        request.GetRecord(),
+		EnablePassword:                request.GetEnablePassword(),
+		EnableCommand:                 request.GetEnableCommand(),
+		EnableExpectedString:          request.GetEnableExpectedString(),
+		EnabledHostnameExpectedString: request.GetEnabledHostnameExpectedString(),
+		Charset:                       request.GetCharset(),
+	}
+}
+
+func toCommandRequest(request *grpcapi.CommandRequest) model.CommandRequest {
+	msgReq := model.CommandRequest{
+		SessionId: request.GetSessionId(),
+		CommandId: int(request.GetCommandId()),
+		Command:   request.GetCommand(),
+		Commands:  request.GetCommands(),
+		OnError:   request.GetOnError(),
+		Raw:       request.GetRaw(),
+		Parse:     request.GetParse(),
+
+		OutputEncoding: request.GetOutputEncoding(),
+		MaxOutputBytes: request.GetMaxOutputBytes(),
+	}
+
+	// bytes of proto are sent as base64 in json request
+	if stdin := request.GetStdin(); len(stdin) > 0 {
+		msgReq.Stdin = base64.StdEncoding.EncodeToString(stdin)
+		msgReq.StdinEncoding = model.StdinEncodingBase64
+	}
+	msgReq.KeepStdinOpen = request.GetKeepStdinOpen()
+
+	for _, step := range request.GetExpect() {
+		msgReq.Expect = append(msgReq.Expect, model.ExpectStep{
+			Expect:     step.GetExpect(),
+			Send:       step.GetSend(),
+			TimeoutSec: int(step.GetTimeoutSec()),
+		})
+	}
+
+	return msgReq
+}
+
+func toProtoResult(res model.CommandResult, parsed []map[string]interface{}, parseError string) *grpcapi.CommandResult {
+	result := &grpcapi.CommandResult{
+		Command:      res.Command,
+		Output:       res.Output,
+		Prompt:       res.Prompt,
+		Mode:         res.Mode,
+		Status:       string(res.Status),
+		Error:        res.Error,
+		ParseError:   parseError,
+		Replacements: int32(res.Replacements),
+		Truncated:    res.Truncated,
+		OutputSize:   res.OutputSize,
+		OutputId:     res.OutputId,
+
+		ReadLimitReached: res.ReadLimitReached,
+	}
+
+	if res.Exit != nil {
+		result.Exit = &grpcapi.ProcessExit{
+			Reason: res.Exit.Reason,
+			Code:   int32(res.Exit.Code),
+			Signal: res.Exit.Signal,
+		}
+	}
+
+	for _, row := range parsed {
+		fields := make(map[string]interface{}, len(row))
+		for name, value := range row {
+			// structpb does not support []string
+			if list, ok := value.([]string); ok {
+				items := make([]interface{}, 0, len(list))
+				for _, item := range list {
+					items = append(items, item)
+				}
+				value = items
+			}
+			fields[name] = value
+		}
+
+		rowStruct, err := structpb.NewStruct(fields)
+		if err != nil {
+			result.ParseError = fmt.Sprintf("convert parsed row. Error: %v", err)
+
+			break
+		}
+		result.Parsed = append(result.Parsed, rowStruct)
+	}
+
+	return result
+}
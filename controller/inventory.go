@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/golang/glog"
+)
+
+const (
+	DeviceNameParam = "name"
+	TagParam        = "tag"
+	GroupParam      = "group"
+)
+
+// List inventory devices. Filter by any of tag and group params
+func (o *HttpController) InventoryDevicesHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "InventoryDevicesHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if request.Method != http.MethodGet {
+		glog.Errorf("%v Wrong message type. Expected: GET. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	query := request.URL.Query()
+	response := model.DeviceListResponse{
+		Devices: o.inventory.List(query[TagParam], query[GroupParam]),
+	}
+
+	o.writeJson(respWriter, logPrefix, response)
+}
+
+// GET, DELETE device by name param. POST create or replace device
+func (o *HttpController) InventoryDeviceHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "InventoryDeviceHandler()"
+	glog.Infof("%v Handle url: %v, Method: %v", logPrefix, request.URL.Path, request.Method)
+
+	switch request.Method {
+	case http.MethodGet:
+		name := request.URL.Query().Get(DeviceNameParam)
+
+		device, err := o.inventory.Get(name)
+		if err != nil {
+			glog.Errorf("%v Error get device. Name: %v, Error: %v", logPrefix, name, err)
+			respWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		o.writeJson(respWriter, logPrefix, device)
+
+	case http.MethodPost:
+		contentTypeHeader := request.Header.Get(ContentTypeHeader)
+		if contentTypeHeader != ContentTypeAppJsonHeader {
+			glog.Errorf("%v Content-Type should be application/json. Content-Type: %v", logPrefix, contentTypeHeader)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		msgReqBytes, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			glog.Errorf("%v Error read POST message. Error: %v", logPrefix, err)
+			respWriter.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		var device model.Device
+		if err := json.Unmarshal(msgReqBytes, &device); err != nil {
+			glog.Errorf("%v Error unmarshal to Device. RawMsg: %s, Error: %v", logPrefix, msgReqBytes, err)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		// stored credentials are used only by devices of inventory files
+		if device.Credential != "" {
+			glog.Errorf("%v Credential can not be set by API. Name: %v, Credential: %v", logPrefix, device.Name, device.Credential)
+			respWriter.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		if err := o.inventory.Put(device); err != nil {
+			glog.Errorf("%v Error put device. Error: %v", logPrefix, err)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		o.writeJson(respWriter, logPrefix, device)
+
+	case http.MethodDelete:
+		name := request.URL.Query().Get(DeviceNameParam)
+
+		if err := o.inventory.Delete(name); err != nil {
+			glog.Errorf("%v Error delete device. Name: %v, Error: %v", logPrefix, name, err)
+			respWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		respWriter.WriteHeader(http.StatusOK)
+
+	default:
+		glog.Errorf("%v Wrong message type. Expected: GET, POST, DELETE. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+	}
+}
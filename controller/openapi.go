@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/openapi"
+)
+
+const (
+	V2OpenApiPath = "/api/v2/openapi.json"
+)
+
+// Serve OpenAPI document of v2 API
+func (o *HttpController) V2OpenApiHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "V2OpenApiHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if request.Method != http.MethodGet {
+		glog.Errorf("%v Wrong message type. Expected: GET. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	o.writeJson(respWriter, logPrefix, v2OpenApiDocument())
+}
+
+// Schemas are generated from models
+func v2OpenApiDocument() map[string]interface{} {
+	schemas := openapi.NewSchemas()
+
+	jsonContent := func(value interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schemas.Ref(value)},
+		}
+	}
+	response := func(description string, value interface{}) map[string]interface{} {
+		res := map[string]interface{}{"description": description}
+		if value != nil {
+			res["content"] = jsonContent(value)
+		}
+
+		return res
+	}
+	errorResponse := func(description string) map[string]interface{} {
+		return response(description, model.ErrorResponse{})
+	}
+	idParam := map[string]interface{}{
+		"name":     "id",
+		"in":       "path",
+		"required": true,
+		"schema":   map[string]interface{}{"type": "string"},
+	}
+
+	paths := map[string]interface{}{
+		V2SessionsPath: map[string]interface{}{
+			"get": map[string]interface{}{
+				"summary":     "List sessions",
+				"operationId": "listSessions",
+				"parameters": []interface{}{map[string]interface{}{
+					"name":   "type",
+					"in":     "query",
+					"schema": map[string]interface{}{"type": "string", "enum": []string{model.SessionTypeConsole, model.SessionTypeTelnet}},
+				}},
+				"responses": map[string]interface{}{
+					"200": response("Sessions", model.SessionListResponse{}),
+				},
+			},
+			"post": map[string]interface{}{
+				"summary":     "Create and connect session",
+				"operationId": "createSession",
+				"requestBody": map[string]interface{}{"required": true, "content": jsonContent(model.SessionCreateRequest{})},
+				"responses": map[string]interface{}{
+					"201": response("Session is connected", model.SessionResource{}),
+					"400": errorResponse("Request is not valid"),
+					"403": errorResponse("Console run-as is not allowed"),
+					"502": errorResponse("Connection to device failed"),
+				},
+			},
+		},
+		V2SessionsPath + "/{id}": map[string]interface{}{
+			"parameters": []interface{}{idParam},
+			"get": map[string]interface{}{
+				"summary":     "Get session",
+				"operationId": "getSession",
+				"responses": map[string]interface{}{
+					"200": response("Session", model.SessionResource{}),
+					"404": errorResponse("Session not found"),
+				},
+			},
+			"delete": map[string]interface{}{
+				"summary":     "Disconnect session",
+				"operationId": "deleteSession",
+				"responses": map[string]interface{}{
+					"204": response("Session is disconnected", nil),
+					"404": errorResponse("Session not found"),
+				},
+			},
+		},
+		V2SessionsPath + "/{id}/" + v2CommandsPath: map[string]interface{}{
+			"parameters": []interface{}{idParam},
+			"post": map[string]interface{}{
+				"summary":     "Execute command or commands. sessionid of body is ignored",
+				"operationId": "executeCommand",
+				"requestBody": map[string]interface{}{"required": true, "content": jsonContent(model.CommandRequest{})},
+				"responses": map[string]interface{}{
+					"200": response("Output", model.CommandResponse{}),
+					"400": errorResponse("Request is not valid"),
+					"404": errorResponse("Session not found"),
+					"502": errorResponse("Command failed"),
+				},
+			},
+		},
+		V2SessionsPath + "/{id}/" + v2StdinPath: map[string]interface{}{
+			"parameters": []interface{}{idParam},
+			"post": map[string]interface{}{
+				"summary":     "Write input of console command started with keepStdinOpen. sessionid of body is ignored",
+				"operationId": "writeStdin",
+				"requestBody": map[string]interface{}{"required": true, "content": jsonContent(model.StdinRequest{})},
+				"responses": map[string]interface{}{
+					"204": response("Input is written", nil),
+					"400": errorResponse("Request is not valid or session does not support stdin"),
+					"404": errorResponse("Session not found"),
+					"409": errorResponse("No running command with open stdin"),
+				},
+			},
+		},
+		V2OutputsPath + "{id}": map[string]interface{}{
+			"parameters": []interface{}{idParam},
+			"get": map[string]interface{}{
+				"summary":     "Read page of whole output of truncated result by outputId. Whole output is read if offset and limit are empty, Range header is supported then",
+				"operationId": "readOutput",
+				"parameters": []interface{}{
+					map[string]interface{}{"name": OffsetParam, "in": "query", "schema": map[string]interface{}{"type": "integer", "minimum": 0}},
+					map[string]interface{}{"name": LimitParam, "in": "query", "schema": map[string]interface{}{"type": "integer", "minimum": 0}},
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "Page bytes. Size of whole output is in " + OutputSizeHeader + " header",
+						"content": map[string]interface{}{
+							"application/octet-stream": map[string]interface{}{"schema": map[string]interface{}{"type": "string", "format": "binary"}},
+						},
+					},
+					"206": map[string]interface{}{"description": "Range of whole output"},
+					"404": errorResponse("Output not found or output store is disabled"),
+					"416": errorResponse("Offset is after the end of output"),
+				},
+			},
+		},
+		V2OpenApiPath: map[string]interface{}{
+			"get": map[string]interface{}{
+				"summary":     "OpenAPI document",
+				"operationId": "getOpenApi",
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "OpenAPI document"},
+				},
+			},
+		},
+	}
+
+	return map[string]interface{}{
+		"openapi": openapi.Version,
+		"info": map[string]interface{}{
+			"title":   "CmdProxy",
+			"version": "2",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas.Components(),
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/golang/glog"
+
+	"github.com/deminds/CmdProxy/charset"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/session"
+)
+
+// Output in encoding of request and count of chars replaced while output is converted to utf8
+func encodeOutput(cmdResult session.CommandResult, encoding string) (string, int) {
+	switch encoding {
+	case model.OutputEncodingBase64:
+		return base64.StdEncoding.EncodeToString([]byte(cmdResult.Output)), 0
+	case model.OutputEncodingRaw:
+		return cmdResult.Output, 0
+	default:
+		return charset.ToUtf8(cmdResult.Output, cmdResult.Charset)
+	}
+}
+
+// Successful result of command with output in encoding cut to max bytes of request
+func (o *HttpController) commandResult(command string, cmdResult session.CommandResult, encoding string, maxBytes int64) model.CommandResult {
+	res := model.CommandResult{
+		Command: command,
+		Prompt:  textOf(cmdResult.Prompt, cmdResult),
+		Mode:    string(cmdResult.Mode),
+		Status:  model.Ok,
+		Exit:    cmdResult.Exit,
+	}
+	cmdResult, res.TruncatedOutput = o.limitOutput(cmdResult, encoding, maxBytes)
+	res.Output, res.Replacements = encodeOutput(cmdResult, encoding)
+
+	return res
+}
+
+// Max output bytes of request. It can only lower limit of service. 0 - unlimited
+func (o *HttpController) outputLimit(maxBytes int64) int64 {
+	if maxBytes > 0 && (o.maxOutputBytes <= 0 || maxBytes < o.maxOutputBytes) {
+		return maxBytes
+	}
+
+	return o.maxOutputBytes
+}
+
+// Cut output to limit before it is encoded. Whole output is saved to output store
+func (o *HttpController) limitOutput(cmdResult session.CommandResult, encoding string, maxBytes int64) (session.CommandResult, model.TruncatedOutput) {
+	logPrefix := "HttpController.limitOutput()"
+
+	limit := o.outputLimit(maxBytes)
+	if limit <= 0 || int64(len(cmdResult.Output)) <= limit {
+		return cmdResult, model.TruncatedOutput{ReadLimitReached: cmdResult.Truncated}
+	}
+
+	truncated := model.TruncatedOutput{
+		Truncated:        true,
+		OutputSize:       int64(len(cmdResult.Output)),
+		ReadLimitReached: cmdResult.Truncated,
+	}
+
+	if o.outputs != nil {
+		id, err := o.outputs.Save(cmdResult.Output)
+		if err != nil {
+			glog.Errorf("%v Error: %v", logPrefix, err)
+		}
+		truncated.OutputId = id
+	}
+
+	glog.Infof("%v Output is truncated. Size: %v, Limit: %v, OutputID: %v", logPrefix, truncated.OutputSize, limit, truncated.OutputId)
+
+	cut := cmdResult.Output[:limit]
+	// char split by limit would be replaced by U+FFFD
+	if encoding != model.OutputEncodingBase64 && encoding != model.OutputEncodingRaw && charset.IsUtf8(cmdResult.Charset) {
+		cut = cutIncompleteRune(cut)
+	}
+	cmdResult.Output = cut
+
+	return cmdResult, truncated
+}
+
+// Remove the last char if its bytes are cut
+func cutIncompleteRune(text string) string {
+	for idx := len(text) - 1; idx >= 0 && idx >= len(text)-utf8.UTFMax; idx-- {
+		if !utf8.RuneStart(text[idx]) {
+			continue
+		}
+
+		if !utf8.FullRuneInString(text[idx:]) {
+			return text[:idx]
+		}
+
+		break
+	}
+
+	return text
+}
+
+// Text of output used by parser and prompt
+func textOf(value string, cmdResult session.CommandResult) string {
+	text, _ := charset.ToUtf8(value, cmdResult.Charset)
+
+	return text
+}
+
+// Output bytes as is instead of json response. Truncation is reported by headers
+func (o *HttpController) writeRaw(respWriter http.ResponseWriter, logPrefix string, msgResp model.CommandResponse) {
+	respWriter.Header().Set(ContentTypeHeader, "application/octet-stream")
+	if msgResp.ReadLimitReached {
+		respWriter.Header().Set(OutputReadLimitHeader, "true")
+	}
+	if msgResp.Truncated {
+		respWriter.Header().Set(OutputTruncatedHeader, "true")
+		respWriter.Header().Set(OutputSizeHeader, strconv.FormatInt(msgResp.OutputSize, 10))
+		if msgResp.OutputId != "" {
+			respWriter.Header().Set(OutputIdHeader, msgResp.OutputId)
+		}
+	}
+	respWriter.WriteHeader(http.StatusOK)
+
+	if _, err := respWriter.Write([]byte(msgResp.Output)); err != nil {
+		glog.Errorf("%v Error write response. Error: %v", logPrefix, err)
+	}
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+
+	"github.com/deminds/CmdProxy/outputs"
+)
+
+const (
+	OffsetParam = "offset"
+	LimitParam  = "limit"
+
+	// Size of whole output returned with every page and with truncated raw output
+	OutputSizeHeader = "X-Output-Size"
+	// Headers of truncated raw output: "true" and id of whole output if it is kept
+	OutputTruncatedHeader = "X-Output-Truncated"
+	OutputIdHeader        = "X-Output-Id"
+	// Output is cut by read limit of session, the rest is not kept
+	OutputReadLimitHeader = "X-Output-Read-Limit-Reached"
+
+	V2OutputsPath = "/api/v2/outputs/"
+)
+
+// Read whole output of truncated result. Path: /api/v1.0/outputs/{id}, /api/v2/outputs/{id}.
+// Page is selected by offset and limit params or by Range header. Page bytes are returned as is
+func (o *HttpController) OutputHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "OutputHandler()"
+	glog.Infof("%v Handle url: %v, Query: %v", logPrefix, request.URL.Path, request.URL.RawQuery)
+
+	if request.Method != http.MethodGet {
+		glog.Errorf("%v Wrong message type. Expected: GET. Actual: %v", logPrefix, request.Method)
+		o.writeError(respWriter, logPrefix, http.StatusMethodNotAllowed, fmt.Errorf("method %v is not allowed", request.Method))
+
+		return
+	}
+
+	if o.outputs == nil {
+		glog.Errorf("%v Output store is disabled", logPrefix)
+		o.writeError(respWriter, logPrefix, http.StatusNotFound, fmt.Errorf("output store is disabled"))
+
+		return
+	}
+
+	outputID := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+
+	offset, err := int64Param(request, OffsetParam)
+	if err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		o.writeError(respWriter, logPrefix, http.StatusBadRequest, err)
+
+		return
+	}
+
+	limit, err := int64Param(request, LimitParam)
+	if err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		o.writeError(respWriter, logPrefix, http.StatusBadRequest, err)
+
+		return
+	}
+
+	file, size, err := o.outputs.Open(outputID)
+	if err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		o.writeError(respWriter, logPrefix, http.StatusNotFound, err)
+
+		return
+	}
+	defer file.Close()
+
+	respWriter.Header().Set(OutputSizeHeader, strconv.FormatInt(size, 10))
+	respWriter.Header().Set(ContentTypeHeader, "application/octet-stream")
+
+	// whole output is served with Range support
+	if offset == 0 && limit == 0 {
+		http.ServeContent(respWriter, request, "", time.Time{}, file)
+
+		return
+	}
+
+	length, err := outputs.PageLength(size, offset, limit)
+	if err != nil {
+		glog.Errorf("%v ID: %v, Error: %v", logPrefix, outputID, err)
+		o.writeError(respWriter, logPrefix, http.StatusRequestedRangeNotSatisfiable, err)
+
+		return
+	}
+
+	// page is copied from file, it is not kept in memory
+	respWriter.Header().Set("Content-Length", strconv.FormatInt(length, 10))
+	respWriter.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(respWriter, io.NewSectionReader(file, offset, length)); err != nil {
+		glog.Errorf("%v Error write response. Error: %v", logPrefix, err)
+	}
+}
+
+// Not negative int param. 0 if param is empty
+func int64Param(request *http.Request, name string) (int64, error) {
+	value := request.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	res, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || res < 0 {
+		return 0, fmt.Errorf("param %v should be not negative number. Value: %v", name, value)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/golang/glog"
+)
+
+const (
+	TemplateNameParam = "name"
+)
+
+// GET list template names
+func (o *HttpController) TemplatesHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "TemplatesHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if request.Method != http.MethodGet {
+		glog.Errorf("%v Wrong message type. Expected: GET. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	response := model.ParseTemplateListResponse{
+		Templates: o.templates.List(),
+	}
+
+	o.writeJson(respWriter, logPrefix, response)
+}
+
+// GET, DELETE template by name param. POST upload or replace template
+func (o *HttpController) TemplateHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "TemplateHandler()"
+	glog.Infof("%v Handle url: %v, Method: %v", logPrefix, request.URL.Path, request.Method)
+
+	switch request.Method {
+	case http.MethodGet:
+		name := request.URL.Query().Get(TemplateNameParam)
+
+		template, err := o.templates.Get(name)
+		if err != nil {
+			glog.Errorf("%v Error get template. Name: %v, Error: %v", logPrefix, name, err)
+			respWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		o.writeJson(respWriter, logPrefix, model.ParseTemplate{
+			Name:     template.GetName(),
+			Template: template.GetText(),
+		})
+
+	case http.MethodPost:
+		contentTypeHeader := request.Header.Get(ContentTypeHeader)
+		if contentTypeHeader != ContentTypeAppJsonHeader {
+			glog.Errorf("%v Content-Type should be application/json. Content-Type: %v", logPrefix, contentTypeHeader)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		msgReqBytes, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			glog.Errorf("%v Error read POST message. Error: %v", logPrefix, err)
+			respWriter.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		var msgReq model.ParseTemplate
+		if err := json.Unmarshal(msgReqBytes, &msgReq); err != nil {
+			glog.Errorf("%v Error unmarshal to ParseTemplate. RawMsg: %s, Error: %v", logPrefix, msgReqBytes, err)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if !msgReq.IsValid() {
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if err := o.templates.Put(msgReq.Name, msgReq.Template); err != nil {
+			glog.Errorf("%v Error put template. Error: %v", logPrefix, err)
+			o.writeError(respWriter, logPrefix, http.StatusBadRequest, err)
+
+			return
+		}
+
+		respWriter.WriteHeader(http.StatusOK)
+
+	case http.MethodDelete:
+		name := request.URL.Query().Get(TemplateNameParam)
+
+		if err := o.templates.Delete(name); err != nil {
+			glog.Errorf("%v Error delete template. Name: %v, Error: %v", logPrefix, name, err)
+			respWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		respWriter.WriteHeader(http.StatusOK)
+
+	default:
+		glog.Errorf("%v Wrong message type. Expected: GET, POST, DELETE. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+	}
+}
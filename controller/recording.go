@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+const (
+	RecordParam = "record"
+
+	recordingPathSuffix        = "/recording"
+	ContentTypeAsciicastHeader = "application/x-asciicast"
+)
+
+// Download session recording. Path: /api/v1.0/sessions/{id}/recording
+func (o *HttpController) SessionRecordingHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "SessionRecordingHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if request.Method != http.MethodGet {
+		glog.Errorf("%v Wrong message type. Expected: GET. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if o.recordings == nil {
+		glog.Errorf("%v Recording is disabled", logPrefix)
+		respWriter.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	if !strings.HasSuffix(request.URL.Path, recordingPathSuffix) {
+		glog.Errorf("%v Unknown path: %v", logPrefix, request.URL.Path)
+		respWriter.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	path := strings.TrimSuffix(request.URL.Path, recordingPathSuffix)
+	sessID := path[strings.LastIndex(path, "/")+1:]
+
+	filePath, err := o.recordings.Path(sessID)
+	if err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if _, err := os.Stat(filePath); err != nil {
+		glog.Errorf("%v Recording not found. ID: %v, Error: %v", logPrefix, sessID, err)
+		respWriter.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	respWriter.Header().Set(ContentTypeHeader, ContentTypeAsciicastHeader)
+	http.ServeFile(respWriter, request, filePath)
+}
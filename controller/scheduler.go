@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/golang/glog"
+)
+
+const (
+	JobIdParam = "id"
+)
+
+// GET list jobs. POST register job
+func (o *HttpController) JobsHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "JobsHandler()"
+	glog.Infof("%v Handle url: %v, Method: %v", logPrefix, request.URL.Path, request.Method)
+
+	switch request.Method {
+	case http.MethodGet:
+		response := model.JobListResponse{
+			Jobs: o.scheduler.List(),
+		}
+
+		o.writeJson(respWriter, logPrefix, response)
+
+	case http.MethodPost:
+		contentTypeHeader := request.Header.Get(ContentTypeHeader)
+		if contentTypeHeader != ContentTypeAppJsonHeader {
+			glog.Errorf("%v Content-Type should be application/json. Content-Type: %v", logPrefix, contentTypeHeader)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		msgReqBytes, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			glog.Errorf("%v Error read POST message. Error: %v", logPrefix, err)
+			respWriter.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		var msgReq model.ScheduledJob
+		if err := json.Unmarshal(msgReqBytes, &msgReq); err != nil {
+			glog.Errorf("%v Error unmarshal to ScheduledJob. RawMsg: %s, Error: %v", logPrefix, msgReqBytes, err)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if !msgReq.IsValid() {
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		job, err := o.scheduler.Add(msgReq)
+		if err != nil {
+			glog.Errorf("%v Error add job. Error: %v", logPrefix, err)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		o.writeJson(respWriter, logPrefix, job)
+
+	default:
+		glog.Errorf("%v Wrong message type. Expected: GET, POST. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// GET job with the last runs, DELETE job by id param
+func (o *HttpController) JobHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "JobHandler()"
+	glog.Infof("%v Handle url: %v, Method: %v", logPrefix, request.URL.Path, request.Method)
+
+	id := request.URL.Query().Get(JobIdParam)
+
+	switch request.Method {
+	case http.MethodGet:
+		response, err := o.scheduler.Get(id)
+		if err != nil {
+			glog.Errorf("%v Error get job. ID: %v, Error: %v", logPrefix, id, err)
+			respWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		o.writeJson(respWriter, logPrefix, response)
+
+	case http.MethodDelete:
+		if err := o.scheduler.Delete(id); err != nil {
+			glog.Errorf("%v Error delete job. ID: %v, Error: %v", logPrefix, id, err)
+			respWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		respWriter.WriteHeader(http.StatusOK)
+
+	default:
+		glog.Errorf("%v Wrong message type. Expected: GET, DELETE. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/session"
+)
+
+// POST more input of console command started with keepStdinOpen
+func (o *HttpController) StdinHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "StdinHandler()"
+	glog.Infof("%v Handle url: %v", logPrefix, request.URL.Path)
+
+	if request.Method != http.MethodPost {
+		glog.Errorf("%v Wrong message type. Expected: POST. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	var msgReq model.StdinRequest
+	if err := o.readJson(request, &msgReq); err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		respWriter.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	status, err := o.writeStdin(msgReq)
+	if err != nil {
+		glog.Errorf("%v Error write stdin. ID: %v, Error: %v", logPrefix, msgReq.SessionId, err)
+	}
+
+	respWriter.WriteHeader(status)
+}
+
+// Return http status of error
+func (o *HttpController) writeStdin(msgReq model.StdinRequest) (int, error) {
+	if !msgReq.IsValid() {
+		return http.StatusBadRequest, fmt.Errorf("stdin request is not valid")
+	}
+
+	sess, err := o.sessionPool.Get(msgReq.SessionId)
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+
+	stdinSess, ok := sess.(session.IStdinSession)
+	if !ok {
+		return http.StatusBadRequest, fmt.Errorf("stdin is not supported by %v session", sess.GetType())
+	}
+
+	// command is not running or its input is closed
+	if err := stdinSess.WriteStdin(msgReq.StdinBytes(), msgReq.Close, msgReq.Secret); err != nil {
+		return http.StatusConflict, err
+	}
+
+	return http.StatusOK, nil
+}
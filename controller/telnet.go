@@ -6,7 +6,7 @@ import (
 	"net/http"
 
 	"github.com/deminds/CmdProxy/model"
-	"github.com/deminds/CmdProxy/session/types"
+	"github.com/deminds/CmdProxy/session"
 	"github.com/golang/glog"
 )
 
@@ -44,38 +44,37 @@ func (o *HttpController) TelnetConnectHandler(respWriter http.ResponseWriter, re
 
 		return
 	}
-	glog.Infof("%v Received POST: %+v", logPrefix, msgReq)
+	glog.Infof("%v Received POST. Device: %v, Host: %v, Port: %v, Login: %v", logPrefix, msgReq.Device, msgReq.Host, msgReq.Port, msgReq.Login)
 
-	if !msgReq.IsValid() {
+	msgReq, err = o.resolveTelnetRequest(msgReq)
+	if err != nil {
+		glog.Errorf("%v Resolve inventory device. Device: %v, Error: %v", logPrefix, msgReq.Device, err)
 		respWriter.WriteHeader(http.StatusBadRequest)
 
 		return
 	}
 
-	sess, err := types.NewTelnetSession(o.idGenerator, o.timeoutSec, msgReq)
-	if err != nil {
-		glog.Errorf("%v NewTelnetSession(). Error: %v", logPrefix, err)
-		respWriter.WriteHeader(http.StatusInternalServerError)
+	if !msgReq.IsValid() {
+		respWriter.WriteHeader(http.StatusBadRequest)
 
 		return
 	}
 
-	if err := sess.Connect(); err != nil {
-		glog.Errorf("%v sess.Connect() Error: %v", logPrefix, err)
+	sess, err := o.connectTelnet(msgReq)
+	if err != nil {
+		glog.Errorf("%v Connect telnet session. Shared: %v, Error: %v", logPrefix, msgReq.Shared, err)
 		respWriter.WriteHeader(http.StatusInternalServerError)
 
 		return
 	}
 
-	o.sessionPool.Put(sess)
-
 	response := model.ConnectResponse{
 		SessionId: sess.GetId(),
 	}
 
 	responseBytes, err := json.Marshal(response)
 	if err != nil {
-		glog.Errorf("%v Error marshal ConnectionResponse to json. sessID: %v Error: %v", sess.GetId(), err)
+		glog.Errorf("%v Error marshal ConnectionResponse to json. sessID: %v Error: %v", logPrefix, sess.GetId(), err)
 		respWriter.WriteHeader(http.StatusInternalServerError)
 
 		return
@@ -89,3 +88,28 @@ func (o *HttpController) TelnetListHandler(respWriter http.ResponseWriter, reque
 	logPrefix := "TelnetListHandler()"
 	glog.Info("%v Handle url: %v", logPrefix, request.URL.Path)
 }
+
+// Create telnet session, connect and put it to pool
+func (o *HttpController) connectTelnet(msgReq model.ConnectTelnetRequest) (session.ISession, error) {
+	sess, err := o.telnetFactory.New(msgReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sess.Connect(); err != nil {
+		return nil, err
+	}
+
+	o.sessionPool.Put(sess)
+
+	return sess, nil
+}
+
+// Fill request from inventory device if device is set
+func (o *HttpController) resolveTelnetRequest(requestData model.ConnectTelnetRequest) (model.ConnectTelnetRequest, error) {
+	if requestData.Device == "" {
+		return requestData, nil
+	}
+
+	return o.inventory.Resolve(requestData)
+}
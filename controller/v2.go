@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/session"
+)
+
+const (
+	V2SessionsPath = "/api/v2/sessions"
+	v2CommandsPath = "commands"
+	v2StdinPath    = "stdin"
+)
+
+// POST create session. GET list sessions
+func (o *HttpController) V2SessionsHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "V2SessionsHandler()"
+	glog.Infof("%v Handle url: %v, Method: %v", logPrefix, request.URL.Path, request.Method)
+
+	switch request.Method {
+	case http.MethodGet:
+		response := model.SessionListResponse{
+			Sessions: []model.SessionResource{},
+		}
+		for _, sess := range o.sessionPool.List(session.SessionType(request.URL.Query().Get("type"))) {
+			response.Sessions = append(response.Sessions, sessionResource(sess))
+		}
+
+		o.writeJson(respWriter, logPrefix, response)
+
+	case http.MethodPost:
+		var msgReq model.SessionCreateRequest
+		if err := o.readJson(request, &msgReq); err != nil {
+			glog.Errorf("%v Error: %v", logPrefix, err)
+			o.writeError(respWriter, logPrefix, http.StatusBadRequest, err)
+
+			return
+		}
+
+		if !msgReq.IsValid() {
+			o.writeError(respWriter, logPrefix, http.StatusBadRequest, fmt.Errorf("type should be console or telnet"))
+
+			return
+		}
+
+		sess, status, err := o.v2Connect(msgReq)
+		if err != nil {
+			glog.Errorf("%v Error connect. Type: %v, Error: %v", logPrefix, msgReq.Type, err)
+			o.writeError(respWriter, logPrefix, status, err)
+
+			return
+		}
+
+		respWriter.Header().Set("Location", V2SessionsPath+"/"+sess.GetId())
+		o.writeJsonStatus(respWriter, logPrefix, http.StatusCreated, sessionResource(sess))
+
+	default:
+		glog.Errorf("%v Wrong message type. Expected: GET, POST. Actual: %v", logPrefix, request.Method)
+		o.writeError(respWriter, logPrefix, http.StatusMethodNotAllowed, fmt.Errorf("method %v is not allowed", request.Method))
+	}
+}
+
+// GET, DELETE /api/v2/sessions/{id}. POST /api/v2/sessions/{id}/commands, /api/v2/sessions/{id}/stdin
+func (o *HttpController) V2SessionHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "V2SessionHandler()"
+	glog.Infof("%v Handle url: %v, Method: %v", logPrefix, request.URL.Path, request.Method)
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(request.URL.Path, V2SessionsPath), "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		o.v2Session(respWriter, request, parts[0])
+	case len(parts) == 2 && parts[1] == v2CommandsPath:
+		o.v2Command(respWriter, request, parts[0])
+	case len(parts) == 2 && parts[1] == v2StdinPath:
+		o.v2Stdin(respWriter, request, parts[0])
+	default:
+		o.writeError(respWriter, logPrefix, http.StatusNotFound, fmt.Errorf("unknown path %v", request.URL.Path))
+	}
+}
+
+func (o *HttpController) v2Session(respWriter http.ResponseWriter, request *http.Request, sessID string) {
+	logPrefix := "v2Session()"
+
+	switch request.Method {
+	case http.MethodGet:
+		sess, err := o.sessionPool.Get(sessID)
+		if err != nil {
+			glog.Errorf("%v Error: %v", logPrefix, err)
+			o.writeError(respWriter, logPrefix, http.StatusNotFound, err)
+
+			return
+		}
+
+		o.writeJson(respWriter, logPrefix, sessionResource(sess))
+
+	case http.MethodDelete:
+		if err := o.sessionPool.RemoveAndClose(sessID); err != nil {
+			glog.Errorf("%v Error remove session. ID: %v, Error: %v", logPrefix, sessID, err)
+			o.writeError(respWriter, logPrefix, http.StatusNotFound, err)
+
+			return
+		}
+
+		respWriter.WriteHeader(http.StatusNoContent)
+
+	default:
+		glog.Errorf("%v Wrong message type. Expected: GET, DELETE. Actual: %v", logPrefix, request.Method)
+		o.writeError(respWriter, logPrefix, http.StatusMethodNotAllowed, fmt.Errorf("method %v is not allowed", request.Method))
+	}
+}
+
+func (o *HttpController) v2Command(respWriter http.ResponseWriter, request *http.Request, sessID string) {
+	logPrefix := "v2Command()"
+
+	if request.Method != http.MethodPost {
+		glog.Errorf("%v Wrong message type. Expected: POST. Actual: %v", logPrefix, request.Method)
+		o.writeError(respWriter, logPrefix, http.StatusMethodNotAllowed, fmt.Errorf("method %v is not allowed", request.Method))
+
+		return
+	}
+
+	var msgReq model.CommandRequest
+	if err := o.readJson(request, &msgReq); err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		o.writeError(respWriter, logPrefix, http.StatusBadRequest, err)
+
+		return
+	}
+	msgReq.SessionId = sessID
+
+	if !msgReq.IsValid() {
+		o.writeError(respWriter, logPrefix, http.StatusBadRequest, fmt.Errorf("command request is not valid"))
+
+		return
+	}
+
+	template, err := o.commandTemplate(msgReq)
+	if err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		o.writeError(respWriter, logPrefix, http.StatusBadRequest, err)
+
+		return
+	}
+
+	sess, err := o.sessionPool.Get(sessID)
+	if err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		o.writeError(respWriter, logPrefix, http.StatusNotFound, err)
+
+		return
+	}
+
+	msgResp, err := o.executeCommand(request.Context(), sess, msgReq, template)
+	if err != nil {
+		glog.Errorf("%v Error execute command. ID: %v, Command: %v, Error: %v", logPrefix, sessID, msgReq.Command, err)
+		o.writeError(respWriter, logPrefix, http.StatusBadGateway, err)
+
+		return
+	}
+
+	if msgReq.OutputEncoding == model.OutputEncodingRaw {
+		o.writeRaw(respWriter, logPrefix, msgResp)
+
+		return
+	}
+
+	o.writeJson(respWriter, logPrefix, msgResp)
+}
+
+func (o *HttpController) v2Stdin(respWriter http.ResponseWriter, request *http.Request, sessID string) {
+	logPrefix := "v2Stdin()"
+
+	if request.Method != http.MethodPost {
+		glog.Errorf("%v Wrong message type. Expected: POST. Actual: %v", logPrefix, request.Method)
+		o.writeError(respWriter, logPrefix, http.StatusMethodNotAllowed, fmt.Errorf("method %v is not allowed", request.Method))
+
+		return
+	}
+
+	var msgReq model.StdinRequest
+	if err := o.readJson(request, &msgReq); err != nil {
+		glog.Errorf("%v Error: %v", logPrefix, err)
+		o.writeError(respWriter, logPrefix, http.StatusBadRequest, err)
+
+		return
+	}
+	msgReq.SessionId = sessID
+
+	if status, err := o.writeStdin(msgReq); err != nil {
+		glog.Errorf("%v Error write stdin. ID: %v, Error: %v", logPrefix, sessID, err)
+		o.writeError(respWriter, logPrefix, status, err)
+
+		return
+	}
+
+	respWriter.WriteHeader(http.StatusNoContent)
+}
+
+// Return http status of error
+func (o *HttpController) v2Connect(msgReq model.SessionCreateRequest) (session.ISession, int, error) {
+	if msgReq.Type == model.SessionTypeConsole {
+		consoleReq := model.ConnectConsoleRequest{
+			Record:         msgReq.Record,
+			ConsoleOptions: msgReq.ConsoleOptions,
+		}
+
+		if !consoleReq.IsValid() {
+			return nil, http.StatusBadRequest, fmt.Errorf("console process settings are not valid")
+		}
+
+		if err := o.consoleFactory.Validate(consoleReq.ConsoleOptions); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+
+		if err := o.consoleFactory.Authorize(consoleReq.ConsoleOptions); err != nil {
+			return nil, http.StatusForbidden, err
+		}
+
+		sess, err := o.connectConsole(consoleReq)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+
+		return sess, http.StatusCreated, nil
+	}
+
+	telnetReq, err := o.resolveTelnetRequest(msgReq.ConnectTelnetRequest)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	if !telnetReq.IsValid() {
+		return nil, http.StatusBadRequest, fmt.Errorf("telnet connection parameters are not valid")
+	}
+
+	sess, err := o.connectTelnet(telnetReq)
+	if err != nil {
+		return nil, http.StatusBadGateway, err
+	}
+
+	return sess, http.StatusCreated, nil
+}
+
+// Check Content-Type and unmarshal body
+func (o *HttpController) readJson(request *http.Request, value interface{}) error {
+	contentTypeHeader := request.Header.Get(ContentTypeHeader)
+	if contentTypeHeader != ContentTypeAppJsonHeader {
+		return fmt.Errorf("Content-Type should be application/json. Content-Type: %v", contentTypeHeader)
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return fmt.Errorf("read body. Error: %v", err)
+	}
+
+	if err := json.Unmarshal(body, value); err != nil {
+		return fmt.Errorf("unmarshal body. Error: %v", err)
+	}
+
+	return nil
+}
+
+func sessionResource(sess session.ISession) model.SessionResource {
+	return model.SessionResource{
+		Id:     sess.GetId(),
+		Type:   string(sess.GetType()),
+		Closed: sess.IsClose(),
+	}
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/golang/glog"
+)
+
+const (
+	WebhookIdParam = "id"
+)
+
+// GET list webhooks. POST register webhook. DELETE webhook by id param
+func (o *HttpController) WebhooksHandler(respWriter http.ResponseWriter, request *http.Request) {
+	logPrefix := "WebhooksHandler()"
+	glog.Infof("%v Handle url: %v, Method: %v", logPrefix, request.URL.Path, request.Method)
+
+	switch request.Method {
+	case http.MethodGet:
+		response := model.WebhookListResponse{
+			Webhooks: o.dispatcher.List(),
+		}
+
+		o.writeJson(respWriter, logPrefix, response)
+
+	case http.MethodPost:
+		contentTypeHeader := request.Header.Get(ContentTypeHeader)
+		if contentTypeHeader != ContentTypeAppJsonHeader {
+			glog.Errorf("%v Content-Type should be application/json. Content-Type: %v", logPrefix, contentTypeHeader)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		msgReqBytes, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			glog.Errorf("%v Error read POST message. Error: %v", logPrefix, err)
+			respWriter.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		var msgReq model.Webhook
+		if err := json.Unmarshal(msgReqBytes, &msgReq); err != nil {
+			glog.Errorf("%v Error unmarshal to Webhook. Error: %v", logPrefix, err)
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if !msgReq.IsValid() {
+			respWriter.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		hook, err := o.dispatcher.Add(msgReq)
+		if err != nil {
+			glog.Errorf("%v Error add webhook. Error: %v", logPrefix, err)
+			respWriter.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+		hook.Secret = ""
+
+		o.writeJson(respWriter, logPrefix, hook)
+
+	case http.MethodDelete:
+		id := request.URL.Query().Get(WebhookIdParam)
+
+		if err := o.dispatcher.Delete(id); err != nil {
+			glog.Errorf("%v Error delete webhook. ID: %v, Error: %v", logPrefix, id, err)
+			respWriter.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		respWriter.WriteHeader(http.StatusOK)
+
+	default:
+		glog.Errorf("%v Wrong message type. Expected: GET, POST, DELETE. Actual: %v", logPrefix, request.Method)
+		respWriter.WriteHeader(http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,1518 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: cmdproxy.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ConnectConsoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Record        bool                   `protobuf:"varint,1,opt,name=record,proto3" json:"record,omitempty"`
+	Uid           *uint32                `protobuf:"varint,2,opt,name=uid,proto3,oneof" json:"uid,omitempty"`
+	Gid           *uint32                `protobuf:"varint,3,opt,name=gid,proto3,oneof" json:"gid,omitempty"`
+	Dir           string                 `protobuf:"bytes,4,opt,name=dir,proto3" json:"dir,omitempty"`
+	Env           map[string]string      `protobuf:"bytes,5,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	EnvMode       string                 `protobuf:"bytes,6,opt,name=env_mode,json=envMode,proto3" json:"env_mode,omitempty"`
+	Umask         string                 `protobuf:"bytes,7,opt,name=umask,proto3" json:"umask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConnectConsoleRequest) Reset() {
+	*x = ConnectConsoleRequest{}
+	mi := &file_cmdproxy_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectConsoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectConsoleRequest) ProtoMessage() {}
+
+func (x *ConnectConsoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectConsoleRequest.ProtoReflect.Descriptor instead.
+func (*ConnectConsoleRequest) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ConnectConsoleRequest) GetRecord() bool {
+	if x != nil {
+		return x.Record
+	}
+	return false
+}
+
+func (x *ConnectConsoleRequest) GetUid() uint32 {
+	if x != nil && x.Uid != nil {
+		return *x.Uid
+	}
+	return 0
+}
+
+func (x *ConnectConsoleRequest) GetGid() uint32 {
+	if x != nil && x.Gid != nil {
+		return *x.Gid
+	}
+	return 0
+}
+
+func (x *ConnectConsoleRequest) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+func (x *ConnectConsoleRequest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ConnectConsoleRequest) GetEnvMode() string {
+	if x != nil {
+		return x.EnvMode
+	}
+	return ""
+}
+
+func (x *ConnectConsoleRequest) GetUmask() string {
+	if x != nil {
+		return x.Umask
+	}
+	return ""
+}
+
+type ConnectTelnetRequest struct {
+	state                         protoimpl.MessageState `protogen:"open.v1"`
+	Device                        string                 `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	Host                          string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Port                          int32                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Login                         string                 `protobuf:"bytes,4,opt,name=login,proto3" json:"login,omitempty"`
+	Password                      string                 `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	LoginExpectedString           string                 `protobuf:"bytes,6,opt,name=login_expected_string,json=loginExpectedString,proto3" json:"login_expected_string,omitempty"`
+	PasswordExpectedString        string                 `protobuf:"bytes,7,opt,name=password_expected_string,json=passwordExpectedString,proto3" json:"password_expected_string,omitempty"`
+	HostnameExpectedString        string                 `protobuf:"bytes,8,opt,name=hostname_expected_string,json=hostnameExpectedString,proto3" json:"hostname_expected_string,omitempty"`
+	ContinueCommandExpectedString string                 `protobuf:"bytes,9,opt,name=continue_command_expected_string,json=continueCommandExpectedString,proto3" json:"continue_command_expected_string,omitempty"`
+	TerminalType                  string                 `protobuf:"bytes,10,opt,name=terminal_type,json=terminalType,proto3" json:"terminal_type,omitempty"`
+	TerminalWidth                 int32                  `protobuf:"varint,11,opt,name=terminal_width,json=terminalWidth,proto3" json:"terminal_width,omitempty"`
+	TerminalHeight                int32                  `protobuf:"varint,12,opt,name=terminal_height,json=terminalHeight,proto3" json:"terminal_height,omitempty"`
+	Shared                        bool                   `protobuf:"varint,13,opt,name=shared,proto3" json:"shared,omitempty"`
+	MaxConnections                int32                  `protobuf:"varint,14,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	Record                        bool                   `protobuf:"varint,15,opt,name=record,proto3" json:"record,omitempty"`
+	EnablePassword                string                 `protobuf:"bytes,16,opt,name=enable_password,json=enablePassword,proto3" json:"enable_password,omitempty"`
+	EnableCommand                 string                 `protobuf:"bytes,17,opt,name=enable_command,json=enableCommand,proto3" json:"enable_command,omitempty"`
+	EnableExpectedString          string                 `protobuf:"bytes,18,opt,name=enable_expected_string,json=enableExpectedString,proto3" json:"enable_expected_string,omitempty"`
+	EnabledHostnameExpectedString string                 `protobuf:"bytes,19,opt,name=enabled_hostname_expected_string,json=enabledHostnameExpectedString,proto3" json:"enabled_hostname_expected_string,omitempty"`
+	Charset                       string                 `protobuf:"bytes,20,opt,name=charset,proto3" json:"charset,omitempty"`
+	ExpectedStringsMode           string                 `protobuf:"bytes,21,opt,name=expected_strings_mode,json=expectedStringsMode,proto3" json:"expected_strings_mode,omitempty"`
+	unknownFields                 protoimpl.UnknownFields
+	sizeCache                     protoimpl.SizeCache
+}
+
+func (x *ConnectTelnetRequest) Reset() {
+	*x = ConnectTelnetRequest{}
+	mi := &file_cmdproxy_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectTelnetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectTelnetRequest) ProtoMessage() {}
+
+func (x *ConnectTelnetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectTelnetRequest.ProtoReflect.Descriptor instead.
+func (*ConnectTelnetRequest) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConnectTelnetRequest) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *ConnectTelnetRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetLoginExpectedString() string {
+	if x != nil {
+		return x.LoginExpectedString
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetPasswordExpectedString() string {
+	if x != nil {
+		return x.PasswordExpectedString
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetHostnameExpectedString() string {
+	if x != nil {
+		return x.HostnameExpectedString
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetContinueCommandExpectedString() string {
+	if x != nil {
+		return x.ContinueCommandExpectedString
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetTerminalType() string {
+	if x != nil {
+		return x.TerminalType
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetTerminalWidth() int32 {
+	if x != nil {
+		return x.TerminalWidth
+	}
+	return 0
+}
+
+func (x *ConnectTelnetRequest) GetTerminalHeight() int32 {
+	if x != nil {
+		return x.TerminalHeight
+	}
+	return 0
+}
+
+func (x *ConnectTelnetRequest) GetShared() bool {
+	if x != nil {
+		return x.Shared
+	}
+	return false
+}
+
+func (x *ConnectTelnetRequest) GetMaxConnections() int32 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+func (x *ConnectTelnetRequest) GetRecord() bool {
+	if x != nil {
+		return x.Record
+	}
+	return false
+}
+
+func (x *ConnectTelnetRequest) GetEnablePassword() string {
+	if x != nil {
+		return x.EnablePassword
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetEnableCommand() string {
+	if x != nil {
+		return x.EnableCommand
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetEnableExpectedString() string {
+	if x != nil {
+		return x.EnableExpectedString
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetEnabledHostnameExpectedString() string {
+	if x != nil {
+		return x.EnabledHostnameExpectedString
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetCharset() string {
+	if x != nil {
+		return x.Charset
+	}
+	return ""
+}
+
+func (x *ConnectTelnetRequest) GetExpectedStringsMode() string {
+	if x != nil {
+		return x.ExpectedStringsMode
+	}
+	return ""
+}
+
+type ConnectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConnectResponse) Reset() {
+	*x = ConnectResponse{}
+	mi := &file_cmdproxy_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectResponse) ProtoMessage() {}
+
+func (x *ConnectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectResponse.ProtoReflect.Descriptor instead.
+func (*ConnectResponse) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConnectResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type ExpectStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expect        string                 `protobuf:"bytes,1,opt,name=expect,proto3" json:"expect,omitempty"`
+	Send          string                 `protobuf:"bytes,2,opt,name=send,proto3" json:"send,omitempty"`
+	TimeoutSec    int32                  `protobuf:"varint,3,opt,name=timeout_sec,json=timeoutSec,proto3" json:"timeout_sec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpectStep) Reset() {
+	*x = ExpectStep{}
+	mi := &file_cmdproxy_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpectStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpectStep) ProtoMessage() {}
+
+func (x *ExpectStep) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpectStep.ProtoReflect.Descriptor instead.
+func (*ExpectStep) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExpectStep) GetExpect() string {
+	if x != nil {
+		return x.Expect
+	}
+	return ""
+}
+
+func (x *ExpectStep) GetSend() string {
+	if x != nil {
+		return x.Send
+	}
+	return ""
+}
+
+func (x *ExpectStep) GetTimeoutSec() int32 {
+	if x != nil {
+		return x.TimeoutSec
+	}
+	return 0
+}
+
+type CommandRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SessionId      string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	CommandId      int32                  `protobuf:"varint,2,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	Command        string                 `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	Commands       []string               `protobuf:"bytes,4,rep,name=commands,proto3" json:"commands,omitempty"`
+	OnError        string                 `protobuf:"bytes,5,opt,name=on_error,json=onError,proto3" json:"on_error,omitempty"`
+	Raw            bool                   `protobuf:"varint,6,opt,name=raw,proto3" json:"raw,omitempty"`
+	Expect         []*ExpectStep          `protobuf:"bytes,7,rep,name=expect,proto3" json:"expect,omitempty"`
+	Parse          string                 `protobuf:"bytes,8,opt,name=parse,proto3" json:"parse,omitempty"`
+	Stdin          []byte                 `protobuf:"bytes,9,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	KeepStdinOpen  bool                   `protobuf:"varint,10,opt,name=keep_stdin_open,json=keepStdinOpen,proto3" json:"keep_stdin_open,omitempty"`
+	OutputEncoding string                 `protobuf:"bytes,11,opt,name=output_encoding,json=outputEncoding,proto3" json:"output_encoding,omitempty"`
+	MaxOutputBytes int64                  `protobuf:"varint,12,opt,name=max_output_bytes,json=maxOutputBytes,proto3" json:"max_output_bytes,omitempty"`
+	StreamOutput   bool                   `protobuf:"varint,13,opt,name=stream_output,json=streamOutput,proto3" json:"stream_output,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CommandRequest) Reset() {
+	*x = CommandRequest{}
+	mi := &file_cmdproxy_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandRequest) ProtoMessage() {}
+
+func (x *CommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandRequest.ProtoReflect.Descriptor instead.
+func (*CommandRequest) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CommandRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *CommandRequest) GetCommandId() int32 {
+	if x != nil {
+		return x.CommandId
+	}
+	return 0
+}
+
+func (x *CommandRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *CommandRequest) GetCommands() []string {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+func (x *CommandRequest) GetOnError() string {
+	if x != nil {
+		return x.OnError
+	}
+	return ""
+}
+
+func (x *CommandRequest) GetRaw() bool {
+	if x != nil {
+		return x.Raw
+	}
+	return false
+}
+
+func (x *CommandRequest) GetExpect() []*ExpectStep {
+	if x != nil {
+		return x.Expect
+	}
+	return nil
+}
+
+func (x *CommandRequest) GetParse() string {
+	if x != nil {
+		return x.Parse
+	}
+	return ""
+}
+
+func (x *CommandRequest) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
+func (x *CommandRequest) GetKeepStdinOpen() bool {
+	if x != nil {
+		return x.KeepStdinOpen
+	}
+	return false
+}
+
+func (x *CommandRequest) GetOutputEncoding() string {
+	if x != nil {
+		return x.OutputEncoding
+	}
+	return ""
+}
+
+func (x *CommandRequest) GetMaxOutputBytes() int64 {
+	if x != nil {
+		return x.MaxOutputBytes
+	}
+	return 0
+}
+
+func (x *CommandRequest) GetStreamOutput() bool {
+	if x != nil {
+		return x.StreamOutput
+	}
+	return false
+}
+
+type CommandResult struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Command          string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	Output           string                 `protobuf:"bytes,2,opt,name=output,proto3" json:"output,omitempty"`
+	Prompt           string                 `protobuf:"bytes,3,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	Mode             string                 `protobuf:"bytes,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	Status           string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Error            string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Parsed           []*structpb.Struct     `protobuf:"bytes,7,rep,name=parsed,proto3" json:"parsed,omitempty"`
+	ParseError       string                 `protobuf:"bytes,8,opt,name=parse_error,json=parseError,proto3" json:"parse_error,omitempty"`
+	Exit             *ProcessExit           `protobuf:"bytes,9,opt,name=exit,proto3" json:"exit,omitempty"`
+	OutputBytes      []byte                 `protobuf:"bytes,10,opt,name=output_bytes,json=outputBytes,proto3" json:"output_bytes,omitempty"`
+	Replacements     int32                  `protobuf:"varint,11,opt,name=replacements,proto3" json:"replacements,omitempty"`
+	Truncated        bool                   `protobuf:"varint,12,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	OutputSize       int64                  `protobuf:"varint,13,opt,name=output_size,json=outputSize,proto3" json:"output_size,omitempty"`
+	OutputId         string                 `protobuf:"bytes,14,opt,name=output_id,json=outputId,proto3" json:"output_id,omitempty"`
+	ReadLimitReached bool                   `protobuf:"varint,15,opt,name=read_limit_reached,json=readLimitReached,proto3" json:"read_limit_reached,omitempty"`
+	OutputChunk      []byte                 `protobuf:"bytes,16,opt,name=output_chunk,json=outputChunk,proto3" json:"output_chunk,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CommandResult) Reset() {
+	*x = CommandResult{}
+	mi := &file_cmdproxy_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResult) ProtoMessage() {}
+
+func (x *CommandResult) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResult.ProtoReflect.Descriptor instead.
+func (*CommandResult) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CommandResult) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *CommandResult) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *CommandResult) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *CommandResult) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *CommandResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CommandResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CommandResult) GetParsed() []*structpb.Struct {
+	if x != nil {
+		return x.Parsed
+	}
+	return nil
+}
+
+func (x *CommandResult) GetParseError() string {
+	if x != nil {
+		return x.ParseError
+	}
+	return ""
+}
+
+func (x *CommandResult) GetExit() *ProcessExit {
+	if x != nil {
+		return x.Exit
+	}
+	return nil
+}
+
+func (x *CommandResult) GetOutputBytes() []byte {
+	if x != nil {
+		return x.OutputBytes
+	}
+	return nil
+}
+
+func (x *CommandResult) GetReplacements() int32 {
+	if x != nil {
+		return x.Replacements
+	}
+	return 0
+}
+
+func (x *CommandResult) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *CommandResult) GetOutputSize() int64 {
+	if x != nil {
+		return x.OutputSize
+	}
+	return 0
+}
+
+func (x *CommandResult) GetOutputId() string {
+	if x != nil {
+		return x.OutputId
+	}
+	return ""
+}
+
+func (x *CommandResult) GetReadLimitReached() bool {
+	if x != nil {
+		return x.ReadLimitReached
+	}
+	return false
+}
+
+func (x *CommandResult) GetOutputChunk() []byte {
+	if x != nil {
+		return x.OutputChunk
+	}
+	return nil
+}
+
+type ProcessExit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	Code          int32                  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Signal        string                 `protobuf:"bytes,3,opt,name=signal,proto3" json:"signal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessExit) Reset() {
+	*x = ProcessExit{}
+	mi := &file_cmdproxy_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessExit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessExit) ProtoMessage() {}
+
+func (x *ProcessExit) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessExit.ProtoReflect.Descriptor instead.
+func (*ProcessExit) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProcessExit) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ProcessExit) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ProcessExit) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+type CommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	CommandId     int32                  `protobuf:"varint,2,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	Result        *CommandResult         `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	Results       []*CommandResult       `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandResponse) Reset() {
+	*x = CommandResponse{}
+	mi := &file_cmdproxy_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResponse) ProtoMessage() {}
+
+func (x *CommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
+func (*CommandResponse) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CommandResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetCommandId() int32 {
+	if x != nil {
+		return x.CommandId
+	}
+	return 0
+}
+
+func (x *CommandResponse) GetResult() *CommandResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *CommandResponse) GetResults() []*CommandResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type StdinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Stdin         []byte                 `protobuf:"bytes,2,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	Close         bool                   `protobuf:"varint,3,opt,name=close,proto3" json:"close,omitempty"`
+	Secret        bool                   `protobuf:"varint,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StdinRequest) Reset() {
+	*x = StdinRequest{}
+	mi := &file_cmdproxy_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StdinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StdinRequest) ProtoMessage() {}
+
+func (x *StdinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StdinRequest.ProtoReflect.Descriptor instead.
+func (*StdinRequest) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StdinRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *StdinRequest) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
+func (x *StdinRequest) GetClose() bool {
+	if x != nil {
+		return x.Close
+	}
+	return false
+}
+
+func (x *StdinRequest) GetSecret() bool {
+	if x != nil {
+		return x.Secret
+	}
+	return false
+}
+
+type StdinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StdinResponse) Reset() {
+	*x = StdinResponse{}
+	mi := &file_cmdproxy_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StdinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StdinResponse) ProtoMessage() {}
+
+func (x *StdinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StdinResponse.ProtoReflect.Descriptor instead.
+func (*StdinResponse) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{9}
+}
+
+type ReadOutputRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OutputId      string                 `protobuf:"bytes,1,opt,name=output_id,json=outputId,proto3" json:"output_id,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit         int64                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadOutputRequest) Reset() {
+	*x = ReadOutputRequest{}
+	mi := &file_cmdproxy_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadOutputRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadOutputRequest) ProtoMessage() {}
+
+func (x *ReadOutputRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadOutputRequest.ProtoReflect.Descriptor instead.
+func (*ReadOutputRequest) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReadOutputRequest) GetOutputId() string {
+	if x != nil {
+		return x.OutputId
+	}
+	return ""
+}
+
+func (x *ReadOutputRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ReadOutputRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ReadOutputResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadOutputResponse) Reset() {
+	*x = ReadOutputResponse{}
+	mi := &file_cmdproxy_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadOutputResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadOutputResponse) ProtoMessage() {}
+
+func (x *ReadOutputResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadOutputResponse.ProtoReflect.Descriptor instead.
+func (*ReadOutputResponse) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReadOutputResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ReadOutputResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type DisconnectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisconnectRequest) Reset() {
+	*x = DisconnectRequest{}
+	mi := &file_cmdproxy_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisconnectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectRequest) ProtoMessage() {}
+
+func (x *DisconnectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectRequest.ProtoReflect.Descriptor instead.
+func (*DisconnectRequest) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DisconnectRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type DisconnectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisconnectResponse) Reset() {
+	*x = DisconnectResponse{}
+	mi := &file_cmdproxy_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisconnectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectResponse) ProtoMessage() {}
+
+func (x *DisconnectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectResponse.ProtoReflect.Descriptor instead.
+func (*DisconnectResponse) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{13}
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_cmdproxy_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_cmdproxy_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Session) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *Session) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_cmdproxy_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmdproxy_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_cmdproxy_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+var File_cmdproxy_proto protoreflect.FileDescriptor
+
+const file_cmdproxy_proto_rawDesc = "" +
+	"\n" +
+	"\x0ecmdproxy.proto\x12\vcmdproxy.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xa7\x02\n" +
+	"\x15ConnectConsoleRequest\x12\x16\n" +
+	"\x06record\x18\x01 \x01(\bR\x06record\x12\x15\n" +
+	"\x03uid\x18\x02 \x01(\rH\x00R\x03uid\x88\x01\x01\x12\x15\n" +
+	"\x03gid\x18\x03 \x01(\rH\x01R\x03gid\x88\x01\x01\x12\x10\n" +
+	"\x03dir\x18\x04 \x01(\tR\x03dir\x12=\n" +
+	"\x03env\x18\x05 \x03(\v2+.cmdproxy.v1.ConnectConsoleRequest.EnvEntryR\x03env\x12\x19\n" +
+	"\benv_mode\x18\x06 \x01(\tR\aenvMode\x12\x14\n" +
+	"\x05umask\x18\a \x01(\tR\x05umask\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x06\n" +
+	"\x04_uidB\x06\n" +
+	"\x04_gid\"\xe4\x06\n" +
+	"\x14ConnectTelnetRequest\x12\x16\n" +
+	"\x06device\x18\x01 \x01(\tR\x06device\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x03 \x01(\x05R\x04port\x12\x14\n" +
+	"\x05login\x18\x04 \x01(\tR\x05login\x12\x1a\n" +
+	"\bpassword\x18\x05 \x01(\tR\bpassword\x122\n" +
+	"\x15login_expected_string\x18\x06 \x01(\tR\x13loginExpectedString\x128\n" +
+	"\x18password_expected_string\x18\a \x01(\tR\x16passwordExpectedString\x128\n" +
+	"\x18hostname_expected_string\x18\b \x01(\tR\x16hostnameExpectedString\x12G\n" +
+	" continue_command_expected_string\x18\t \x01(\tR\x1dcontinueCommandExpectedString\x12#\n" +
+	"\rterminal_type\x18\n" +
+	" \x01(\tR\fterminalType\x12%\n" +
+	"\x0eterminal_width\x18\v \x01(\x05R\rterminalWidth\x12'\n" +
+	"\x0fterminal_height\x18\f \x01(\x05R\x0eterminalHeight\x12\x16\n" +
+	"\x06shared\x18\r \x01(\bR\x06shared\x12'\n" +
+	"\x0fmax_connections\x18\x0e \x01(\x05R\x0emaxConnections\x12\x16\n" +
+	"\x06record\x18\x0f \x01(\bR\x06record\x12'\n" +
+	"\x0fenable_password\x18\x10 \x01(\tR\x0eenablePassword\x12%\n" +
+	"\x0eenable_command\x18\x11 \x01(\tR\renableCommand\x124\n" +
+	"\x16enable_expected_string\x18\x12 \x01(\tR\x14enableExpectedString\x12G\n" +
+	" enabled_hostname_expected_string\x18\x13 \x01(\tR\x1denabledHostnameExpectedString\x12\x18\n" +
+	"\acharset\x18\x14 \x01(\tR\acharset\x122\n" +
+	"\x15expected_strings_mode\x18\x15 \x01(\tR\x13expectedStringsMode\"0\n" +
+	"\x0fConnectResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"Y\n" +
+	"\n" +
+	"ExpectStep\x12\x16\n" +
+	"\x06expect\x18\x01 \x01(\tR\x06expect\x12\x12\n" +
+	"\x04send\x18\x02 \x01(\tR\x04send\x12\x1f\n" +
+	"\vtimeout_sec\x18\x03 \x01(\x05R\n" +
+	"timeoutSec\"\xae\x03\n" +
+	"\x0eCommandRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x02 \x01(\x05R\tcommandId\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x1a\n" +
+	"\bcommands\x18\x04 \x03(\tR\bcommands\x12\x19\n" +
+	"\bon_error\x18\x05 \x01(\tR\aonError\x12\x10\n" +
+	"\x03raw\x18\x06 \x01(\bR\x03raw\x12/\n" +
+	"\x06expect\x18\a \x03(\v2\x17.cmdproxy.v1.ExpectStepR\x06expect\x12\x14\n" +
+	"\x05parse\x18\b \x01(\tR\x05parse\x12\x14\n" +
+	"\x05stdin\x18\t \x01(\fR\x05stdin\x12&\n" +
+	"\x0fkeep_stdin_open\x18\n" +
+	" \x01(\bR\rkeepStdinOpen\x12'\n" +
+	"\x0foutput_encoding\x18\v \x01(\tR\x0eoutputEncoding\x12(\n" +
+	"\x10max_output_bytes\x18\f \x01(\x03R\x0emaxOutputBytes\x12#\n" +
+	"\rstream_output\x18\r \x01(\bR\fstreamOutput\"\x8f\x04\n" +
+	"\rCommandResult\x12\x18\n" +
+	"\acommand\x18\x01 \x01(\tR\acommand\x12\x16\n" +
+	"\x06output\x18\x02 \x01(\tR\x06output\x12\x16\n" +
+	"\x06prompt\x18\x03 \x01(\tR\x06prompt\x12\x12\n" +
+	"\x04mode\x18\x04 \x01(\tR\x04mode\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12/\n" +
+	"\x06parsed\x18\a \x03(\v2\x17.google.protobuf.StructR\x06parsed\x12\x1f\n" +
+	"\vparse_error\x18\b \x01(\tR\n" +
+	"parseError\x12,\n" +
+	"\x04exit\x18\t \x01(\v2\x18.cmdproxy.v1.ProcessExitR\x04exit\x12!\n" +
+	"\foutput_bytes\x18\n" +
+	" \x01(\fR\voutputBytes\x12\"\n" +
+	"\freplacements\x18\v \x01(\x05R\freplacements\x12\x1c\n" +
+	"\ttruncated\x18\f \x01(\bR\ttruncated\x12\x1f\n" +
+	"\voutput_size\x18\r \x01(\x03R\n" +
+	"outputSize\x12\x1b\n" +
+	"\toutput_id\x18\x0e \x01(\tR\boutputId\x12,\n" +
+	"\x12read_limit_reached\x18\x0f \x01(\bR\x10readLimitReached\x12!\n" +
+	"\foutput_chunk\x18\x10 \x01(\fR\voutputChunk\"Q\n" +
+	"\vProcessExit\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\x05R\x04code\x12\x16\n" +
+	"\x06signal\x18\x03 \x01(\tR\x06signal\"\xb9\x01\n" +
+	"\x0fCommandResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x02 \x01(\x05R\tcommandId\x122\n" +
+	"\x06result\x18\x03 \x01(\v2\x1a.cmdproxy.v1.CommandResultR\x06result\x124\n" +
+	"\aresults\x18\x04 \x03(\v2\x1a.cmdproxy.v1.CommandResultR\aresults\"q\n" +
+	"\fStdinRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05stdin\x18\x02 \x01(\fR\x05stdin\x12\x14\n" +
+	"\x05close\x18\x03 \x01(\bR\x05close\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\bR\x06secret\"\x0f\n" +
+	"\rStdinResponse\"^\n" +
+	"\x11ReadOutputRequest\x12\x1b\n" +
+	"\toutput_id\x18\x01 \x01(\tR\boutputId\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x03R\x05limit\"<\n" +
+	"\x12ReadOutputResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\"2\n" +
+	"\x11DisconnectRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\x14\n" +
+	"\x12DisconnectResponse\"!\n" +
+	"\vListRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\"<\n" +
+	"\aSession\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\"@\n" +
+	"\fListResponse\x120\n" +
+	"\bsessions\x18\x01 \x03(\v2\x14.cmdproxy.v1.SessionR\bsessions2\xd9\x04\n" +
+	"\bCmdProxy\x12R\n" +
+	"\x0eConnectConsole\x12\".cmdproxy.v1.ConnectConsoleRequest\x1a\x1c.cmdproxy.v1.ConnectResponse\x12P\n" +
+	"\rConnectTelnet\x12!.cmdproxy.v1.ConnectTelnetRequest\x1a\x1c.cmdproxy.v1.ConnectResponse\x12D\n" +
+	"\aCommand\x12\x1b.cmdproxy.v1.CommandRequest\x1a\x1c.cmdproxy.v1.CommandResponse\x12A\n" +
+	"\x04Exec\x12\x1b.cmdproxy.v1.CommandRequest\x1a\x1a.cmdproxy.v1.CommandResult0\x01\x12C\n" +
+	"\n" +
+	"WriteStdin\x12\x19.cmdproxy.v1.StdinRequest\x1a\x1a.cmdproxy.v1.StdinResponse\x12M\n" +
+	"\n" +
+	"ReadOutput\x12\x1e.cmdproxy.v1.ReadOutputRequest\x1a\x1f.cmdproxy.v1.ReadOutputResponse\x12M\n" +
+	"\n" +
+	"Disconnect\x12\x1e.cmdproxy.v1.DisconnectRequest\x1a\x1f.cmdproxy.v1.DisconnectResponse\x12;\n" +
+	"\x04List\x12\x18.cmdproxy.v1.ListRequest\x1a\x19.cmdproxy.v1.ListResponseB%Z#github.com/deminds/CmdProxy/grpcapib\x06proto3"
+
+var (
+	file_cmdproxy_proto_rawDescOnce sync.Once
+	file_cmdproxy_proto_rawDescData []byte
+)
+
+func file_cmdproxy_proto_rawDescGZIP() []byte {
+	file_cmdproxy_proto_rawDescOnce.Do(func() {
+		file_cmdproxy_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_cmdproxy_proto_rawDesc), len(file_cmdproxy_proto_rawDesc)))
+	})
+	return file_cmdproxy_proto_rawDescData
+}
+
+var file_cmdproxy_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_cmdproxy_proto_goTypes = []any{
+	(*ConnectConsoleRequest)(nil), // 0: cmdproxy.v1.ConnectConsoleRequest
+	(*ConnectTelnetRequest)(nil),  // 1: cmdproxy.v1.ConnectTelnetRequest
+	(*ConnectResponse)(nil),       // 2: cmdproxy.v1.ConnectResponse
+	(*ExpectStep)(nil),            // 3: cmdproxy.v1.ExpectStep
+	(*CommandRequest)(nil),        // 4: cmdproxy.v1.CommandRequest
+	(*CommandResult)(nil),         // 5: cmdproxy.v1.CommandResult
+	(*ProcessExit)(nil),           // 6: cmdproxy.v1.ProcessExit
+	(*CommandResponse)(nil),       // 7: cmdproxy.v1.CommandResponse
+	(*StdinRequest)(nil),          // 8: cmdproxy.v1.StdinRequest
+	(*StdinResponse)(nil),         // 9: cmdproxy.v1.StdinResponse
+	(*ReadOutputRequest)(nil),     // 10: cmdproxy.v1.ReadOutputRequest
+	(*ReadOutputResponse)(nil),    // 11: cmdproxy.v1.ReadOutputResponse
+	(*DisconnectRequest)(nil),     // 12: cmdproxy.v1.DisconnectRequest
+	(*DisconnectResponse)(nil),    // 13: cmdproxy.v1.DisconnectResponse
+	(*ListRequest)(nil),           // 14: cmdproxy.v1.ListRequest
+	(*Session)(nil),               // 15: cmdproxy.v1.Session
+	(*ListResponse)(nil),          // 16: cmdproxy.v1.ListResponse
+	nil,                           // 17: cmdproxy.v1.ConnectConsoleRequest.EnvEntry
+	(*structpb.Struct)(nil),       // 18: google.protobuf.Struct
+}
+var file_cmdproxy_proto_depIdxs = []int32{
+	17, // 0: cmdproxy.v1.ConnectConsoleRequest.env:type_name -> cmdproxy.v1.ConnectConsoleRequest.EnvEntry
+	3,  // 1: cmdproxy.v1.CommandRequest.expect:type_name -> cmdproxy.v1.ExpectStep
+	18, // 2: cmdproxy.v1.CommandResult.parsed:type_name -> google.protobuf.Struct
+	6,  // 3: cmdproxy.v1.CommandResult.exit:type_name -> cmdproxy.v1.ProcessExit
+	5,  // 4: cmdproxy.v1.CommandResponse.result:type_name -> cmdproxy.v1.CommandResult
+	5,  // 5: cmdproxy.v1.CommandResponse.results:type_name -> cmdproxy.v1.CommandResult
+	15, // 6: cmdproxy.v1.ListResponse.sessions:type_name -> cmdproxy.v1.Session
+	0,  // 7: cmdproxy.v1.CmdProxy.ConnectConsole:input_type -> cmdproxy.v1.ConnectConsoleRequest
+	1,  // 8: cmdproxy.v1.CmdProxy.ConnectTelnet:input_type -> cmdproxy.v1.ConnectTelnetRequest
+	4,  // 9: cmdproxy.v1.CmdProxy.Command:input_type -> cmdproxy.v1.CommandRequest
+	4,  // 10: cmdproxy.v1.CmdProxy.Exec:input_type -> cmdproxy.v1.CommandRequest
+	8,  // 11: cmdproxy.v1.CmdProxy.WriteStdin:input_type -> cmdproxy.v1.StdinRequest
+	10, // 12: cmdproxy.v1.CmdProxy.ReadOutput:input_type -> cmdproxy.v1.ReadOutputRequest
+	12, // 13: cmdproxy.v1.CmdProxy.Disconnect:input_type -> cmdproxy.v1.DisconnectRequest
+	14, // 14: cmdproxy.v1.CmdProxy.List:input_type -> cmdproxy.v1.ListRequest
+	2,  // 15: cmdproxy.v1.CmdProxy.ConnectConsole:output_type -> cmdproxy.v1.ConnectResponse
+	2,  // 16: cmdproxy.v1.CmdProxy.ConnectTelnet:output_type -> cmdproxy.v1.ConnectResponse
+	7,  // 17: cmdproxy.v1.CmdProxy.Command:output_type -> cmdproxy.v1.CommandResponse
+	5,  // 18: cmdproxy.v1.CmdProxy.Exec:output_type -> cmdproxy.v1.CommandResult
+	9,  // 19: cmdproxy.v1.CmdProxy.WriteStdin:output_type -> cmdproxy.v1.StdinResponse
+	11, // 20: cmdproxy.v1.CmdProxy.ReadOutput:output_type -> cmdproxy.v1.ReadOutputResponse
+	13, // 21: cmdproxy.v1.CmdProxy.Disconnect:output_type -> cmdproxy.v1.DisconnectResponse
+	16, // 22: cmdproxy.v1.CmdProxy.List:output_type -> cmdproxy.v1.ListResponse
+	15, // [15:23] is the sub-list for method output_type
+	7,  // [7:15] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_cmdproxy_proto_init() }
+func file_cmdproxy_proto_init() {
+	if File_cmdproxy_proto != nil {
+		return
+	}
+	file_cmdproxy_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cmdproxy_proto_rawDesc), len(file_cmdproxy_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_cmdproxy_proto_goTypes,
+		DependencyIndexes: file_cmdproxy_proto_depIdxs,
+		MessageInfos:      file_cmdproxy_proto_msgTypes,
+	}.Build()
+	File_cmdproxy_proto = out.File
+	file_cmdproxy_proto_goTypes = nil
+	file_cmdproxy_proto_depIdxs = nil
+}
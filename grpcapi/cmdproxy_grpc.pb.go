@@ -0,0 +1,391 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: cmdproxy.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CmdProxy_ConnectConsole_FullMethodName = "/cmdproxy.v1.CmdProxy/ConnectConsole"
+	CmdProxy_ConnectTelnet_FullMethodName  = "/cmdproxy.v1.CmdProxy/ConnectTelnet"
+	CmdProxy_Command_FullMethodName        = "/cmdproxy.v1.CmdProxy/Command"
+	CmdProxy_Exec_FullMethodName           = "/cmdproxy.v1.CmdProxy/Exec"
+	CmdProxy_WriteStdin_FullMethodName     = "/cmdproxy.v1.CmdProxy/WriteStdin"
+	CmdProxy_ReadOutput_FullMethodName     = "/cmdproxy.v1.CmdProxy/ReadOutput"
+	CmdProxy_Disconnect_FullMethodName     = "/cmdproxy.v1.CmdProxy/Disconnect"
+	CmdProxy_List_FullMethodName           = "/cmdproxy.v1.CmdProxy/List"
+)
+
+// CmdProxyClient is the client API for CmdProxy service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CmdProxyClient interface {
+	ConnectConsole(ctx context.Context, in *ConnectConsoleRequest, opts ...grpc.CallOption) (*ConnectResponse, error)
+	ConnectTelnet(ctx context.Context, in *ConnectTelnetRequest, opts ...grpc.CallOption) (*ConnectResponse, error)
+	Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	Exec(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CommandResult], error)
+	WriteStdin(ctx context.Context, in *StdinRequest, opts ...grpc.CallOption) (*StdinResponse, error)
+	ReadOutput(ctx context.Context, in *ReadOutputRequest, opts ...grpc.CallOption) (*ReadOutputResponse, error)
+	Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*DisconnectResponse, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+}
+
+type cmdProxyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCmdProxyClient(cc grpc.ClientConnInterface) CmdProxyClient {
+	return &cmdProxyClient{cc}
+}
+
+func (c *cmdProxyClient) ConnectConsole(ctx context.Context, in *ConnectConsoleRequest, opts ...grpc.CallOption) (*ConnectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConnectResponse)
+	err := c.cc.Invoke(ctx, CmdProxy_ConnectConsole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdProxyClient) ConnectTelnet(ctx context.Context, in *ConnectTelnetRequest, opts ...grpc.CallOption) (*ConnectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConnectResponse)
+	err := c.cc.Invoke(ctx, CmdProxy_ConnectTelnet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdProxyClient) Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, CmdProxy_Command_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdProxyClient) Exec(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CommandResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CmdProxy_ServiceDesc.Streams[0], CmdProxy_Exec_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CommandRequest, CommandResult]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CmdProxy_ExecClient = grpc.ServerStreamingClient[CommandResult]
+
+func (c *cmdProxyClient) WriteStdin(ctx context.Context, in *StdinRequest, opts ...grpc.CallOption) (*StdinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StdinResponse)
+	err := c.cc.Invoke(ctx, CmdProxy_WriteStdin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdProxyClient) ReadOutput(ctx context.Context, in *ReadOutputRequest, opts ...grpc.CallOption) (*ReadOutputResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReadOutputResponse)
+	err := c.cc.Invoke(ctx, CmdProxy_ReadOutput_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdProxyClient) Disconnect(ctx context.Context, in *DisconnectRequest, opts ...grpc.CallOption) (*DisconnectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisconnectResponse)
+	err := c.cc.Invoke(ctx, CmdProxy_Disconnect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdProxyClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, CmdProxy_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CmdProxyServer is the server API for CmdProxy service.
+// All implementations must embed UnimplementedCmdProxyServer
+// for forward compatibility.
+type CmdProxyServer interface {
+	ConnectConsole(context.Context, *ConnectConsoleRequest) (*ConnectResponse, error)
+	ConnectTelnet(context.Context, *ConnectTelnetRequest) (*ConnectResponse, error)
+	Command(context.Context, *CommandRequest) (*CommandResponse, error)
+	Exec(*CommandRequest, grpc.ServerStreamingServer[CommandResult]) error
+	WriteStdin(context.Context, *StdinRequest) (*StdinResponse, error)
+	ReadOutput(context.Context, *ReadOutputRequest) (*ReadOutputResponse, error)
+	Disconnect(context.Context, *DisconnectRequest) (*DisconnectResponse, error)
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	mustEmbedUnimplementedCmdProxyServer()
+}
+
+// UnimplementedCmdProxyServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCmdProxyServer struct{}
+
+func (UnimplementedCmdProxyServer) ConnectConsole(context.Context, *ConnectConsoleRequest) (*ConnectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConnectConsole not implemented")
+}
+func (UnimplementedCmdProxyServer) ConnectTelnet(context.Context, *ConnectTelnetRequest) (*ConnectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConnectTelnet not implemented")
+}
+func (UnimplementedCmdProxyServer) Command(context.Context, *CommandRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Command not implemented")
+}
+func (UnimplementedCmdProxyServer) Exec(*CommandRequest, grpc.ServerStreamingServer[CommandResult]) error {
+	return status.Errorf(codes.Unimplemented, "method Exec not implemented")
+}
+func (UnimplementedCmdProxyServer) WriteStdin(context.Context, *StdinRequest) (*StdinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteStdin not implemented")
+}
+func (UnimplementedCmdProxyServer) ReadOutput(context.Context, *ReadOutputRequest) (*ReadOutputResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadOutput not implemented")
+}
+func (UnimplementedCmdProxyServer) Disconnect(context.Context, *DisconnectRequest) (*DisconnectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Disconnect not implemented")
+}
+func (UnimplementedCmdProxyServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedCmdProxyServer) mustEmbedUnimplementedCmdProxyServer() {}
+func (UnimplementedCmdProxyServer) testEmbeddedByValue()                  {}
+
+// UnsafeCmdProxyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CmdProxyServer will
+// result in compilation errors.
+type UnsafeCmdProxyServer interface {
+	mustEmbedUnimplementedCmdProxyServer()
+}
+
+func RegisterCmdProxyServer(s grpc.ServiceRegistrar, srv CmdProxyServer) {
+	// If the following call pancis, it indicates UnimplementedCmdProxyServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CmdProxy_ServiceDesc, srv)
+}
+
+func _CmdProxy_ConnectConsole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectConsoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdProxyServer).ConnectConsole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CmdProxy_ConnectConsole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdProxyServer).ConnectConsole(ctx, req.(*ConnectConsoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CmdProxy_ConnectTelnet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectTelnetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdProxyServer).ConnectTelnet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CmdProxy_ConnectTelnet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdProxyServer).ConnectTelnet(ctx, req.(*ConnectTelnetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CmdProxy_Command_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdProxyServer).Command(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CmdProxy_Command_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdProxyServer).Command(ctx, req.(*CommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CmdProxy_Exec_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CommandRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CmdProxyServer).Exec(m, &grpc.GenericServerStream[CommandRequest, CommandResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CmdProxy_ExecServer = grpc.ServerStreamingServer[CommandResult]
+
+func _CmdProxy_WriteStdin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StdinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdProxyServer).WriteStdin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CmdProxy_WriteStdin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdProxyServer).WriteStdin(ctx, req.(*StdinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CmdProxy_ReadOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadOutputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdProxyServer).ReadOutput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CmdProxy_ReadOutput_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdProxyServer).ReadOutput(ctx, req.(*ReadOutputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CmdProxy_Disconnect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdProxyServer).Disconnect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CmdProxy_Disconnect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdProxyServer).Disconnect(ctx, req.(*DisconnectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CmdProxy_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdProxyServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CmdProxy_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdProxyServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CmdProxy_ServiceDesc is the grpc.ServiceDesc for CmdProxy service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CmdProxy_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cmdproxy.v1.CmdProxy",
+	HandlerType: (*CmdProxyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ConnectConsole",
+			Handler:    _CmdProxy_ConnectConsole_Handler,
+		},
+		{
+			MethodName: "ConnectTelnet",
+			Handler:    _CmdProxy_ConnectTelnet_Handler,
+		},
+		{
+			MethodName: "Command",
+			Handler:    _CmdProxy_Command_Handler,
+		},
+		{
+			MethodName: "WriteStdin",
+			Handler:    _CmdProxy_WriteStdin_Handler,
+		},
+		{
+			MethodName: "ReadOutput",
+			Handler:    _CmdProxy_ReadOutput_Handler,
+		},
+		{
+			MethodName: "Disconnect",
+			Handler:    _CmdProxy_Disconnect_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _CmdProxy_List_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Exec",
+			Handler:       _CmdProxy_Exec_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cmdproxy.proto",
+}
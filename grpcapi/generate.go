@@ -0,0 +1,4 @@
+// Package grpcapi contains gRPC service definition and generated code
+package grpcapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cmdproxy.proto
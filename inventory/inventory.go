@@ -0,0 +1,232 @@
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+const (
+	DefaultTelnetPort = 23
+)
+
+func NewInventory() *Inventory {
+	return &Inventory{
+		devices:     map[string]model.Device{},
+		profiles:    map[string]Profile{},
+		credentials: map[string]Credential{},
+		mutex:       sync.RWMutex{},
+	}
+}
+
+type Inventory struct {
+	devices     map[string]model.Device
+	profiles    map[string]Profile
+	credentials map[string]Credential
+	mutex       sync.RWMutex
+}
+
+func (o *Inventory) Get(name string) (model.Device, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	device, exist := o.devices[name]
+	if !exist {
+		return model.Device{}, fmt.Errorf("Inventory.Get() Device not found. Name: %v", name)
+	}
+
+	return device, nil
+}
+
+// Return devices sorted by name which have any of tags or groups.
+// All devices are returned if tags and groups are empty
+func (o *Inventory) List(tags []string, groups []string) []model.Device {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	res := []model.Device{}
+	for _, device := range o.devices {
+		if (len(tags) == 0 && len(groups) == 0) ||
+			intersects(device.Tags, tags) ||
+			intersects(device.Groups, groups) {
+
+			res = append(res, device)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
+
+// Create or replace device
+func (o *Inventory) Put(device model.Device) error {
+	if !device.IsValid() {
+		return fmt.Errorf("Inventory.Put() Device is not valid. Name: %v", device.Name)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if device.Profile != "" {
+		if _, exist := o.profiles[device.Profile]; !exist {
+			return fmt.Errorf("Inventory.Put() Profile not found. Name: %v, Profile: %v", device.Name, device.Profile)
+		}
+	}
+
+	if device.Credential != "" {
+		if _, exist := o.credentials[device.Credential]; !exist {
+			return fmt.Errorf("Inventory.Put() Credential not found. Name: %v, Credential: %v", device.Name, device.Credential)
+		}
+	}
+
+	o.devices[device.Name] = device
+
+	return nil
+}
+
+func (o *Inventory) Delete(name string) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, exist := o.devices[name]; !exist {
+		return fmt.Errorf("Inventory.Delete() Device not found. Name: %v", name)
+	}
+
+	delete(o.devices, name)
+
+	return nil
+}
+
+func (o *Inventory) GetProfile(name string) (Profile, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	profile, exist := o.profiles[name]
+	if !exist {
+		return Profile{}, fmt.Errorf("Inventory.GetProfile() Profile not found. Name: %v", name)
+	}
+
+	return profile, nil
+}
+
+func (o *Inventory) PutProfile(name string, profile Profile) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.profiles[name] = profile
+}
+
+func (o *Inventory) PutCredential(name string, credential Credential) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.credentials[name] = credential
+}
+
+// Build connect request of device. Profile and credential of device are defaults,
+// fields set in request override them. Host and port can not be overridden,
+// otherwise stored credential would be sent to any host
+func (o *Inventory) Resolve(request model.ConnectTelnetRequest) (model.ConnectTelnetRequest, error) {
+	if request.Host != "" || request.Port != 0 {
+		return request, fmt.Errorf("Inventory.Resolve() Host and port can not be set with device. Device: %v, Host: %v, Port: %v",
+			request.Device, request.Host, request.Port)
+	}
+
+	device, err := o.Get(request.Device)
+	if err != nil {
+		return request, err
+	}
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	res := model.ConnectTelnetRequest{
+		Host: device.Host,
+		Port: device.Port,
+	}
+
+	if device.Profile != "" {
+		profile, exist := o.profiles[device.Profile]
+		if !exist {
+			return request, fmt.Errorf("Inventory.Resolve() Profile not found. Name: %v, Profile: %v", device.Name, device.Profile)
+		}
+
+		if res.Port == 0 {
+			res.Port = profile.Port
+		}
+		res.LoginExpectedString = profile.LoginExpectedString
+		res.PasswordExpectedString = profile.PasswordExpectedString
+		res.HostnameExpectedString = profile.HostnameExpectedString
+		res.ContinueCommandExpectedString = profile.ContinueCommandExpectedString
+		res.ExpectedStringsMode = profile.ExpectedStringsMode
+		res.EnableCommand = profile.EnableCommand
+		res.EnableExpectedString = profile.EnableExpectedString
+		res.EnabledHostnameExpectedString = profile.EnabledHostnameExpectedString
+		res.TerminalType = profile.TerminalType
+		res.TerminalWidth = profile.TerminalWidth
+		res.TerminalHeight = profile.TerminalHeight
+		res.Charset = profile.Charset
+	}
+
+	if device.Credential != "" {
+		credential, exist := o.credentials[device.Credential]
+		if !exist {
+			return request, fmt.Errorf("Inventory.Resolve() Credential not found. Name: %v, Credential: %v", device.Name, device.Credential)
+		}
+
+		res.Login = credential.Login
+		res.Password = credential.Password
+		res.EnablePassword = credential.EnablePassword
+	}
+
+	if res.Port == 0 {
+		res.Port = DefaultTelnetPort
+	}
+
+	override(&res.Login, request.Login)
+	override(&res.Password, request.Password)
+	override(&res.LoginExpectedString, request.LoginExpectedString)
+	override(&res.PasswordExpectedString, request.PasswordExpectedString)
+	override(&res.HostnameExpectedString, request.HostnameExpectedString)
+	override(&res.ContinueCommandExpectedString, request.ContinueCommandExpectedString)
+	override(&res.ExpectedStringsMode, request.ExpectedStringsMode)
+	override(&res.EnablePassword, request.EnablePassword)
+	override(&res.EnableCommand, request.EnableCommand)
+	override(&res.EnableExpectedString, request.EnableExpectedString)
+	override(&res.EnabledHostnameExpectedString, request.EnabledHostnameExpectedString)
+	override(&res.Charset, request.Charset)
+	override(&res.TerminalType, request.TerminalType)
+	if request.TerminalWidth != 0 {
+		res.TerminalWidth = request.TerminalWidth
+	}
+	if request.TerminalHeight != 0 {
+		res.TerminalHeight = request.TerminalHeight
+	}
+
+	res.Device = device.Name
+	res.Shared = request.Shared
+	res.MaxConnections = request.MaxConnections
+
+	return res, nil
+}
+
+func override(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+func intersects(a []string, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+
+	return false
+}
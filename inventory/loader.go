@@ -0,0 +1,153 @@
+package inventory
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/deminds/CmdProxy/model"
+	"github.com/golang/glog"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// separator of tags and groups in csv
+	CsvListSeparator = ";"
+)
+
+// Header of csv file. Tags and groups are separated by CsvListSeparator
+var csvColumns = []string{"name", "host", "port", "protocol", "profile", "credential", "tags", "groups"}
+
+type inventoryFile struct {
+	Profiles    map[string]Profile    `yaml:"profiles"`
+	Credentials map[string]Credential `yaml:"credentials"`
+	Devices     []model.Device        `yaml:"devices"`
+}
+
+// Load yaml file with profiles, credentials and devices or csv file with devices
+func (o *Inventory) LoadFile(path string) error {
+	logPrefix := "Inventory.LoadFile()"
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return o.loadYaml(path)
+	case ".csv":
+		return o.loadCsv(path)
+	}
+
+	return fmt.Errorf("%v Unknown file type. Expected: yaml, csv. Path: %v", logPrefix, path)
+}
+
+func (o *Inventory) loadYaml(path string) error {
+	logPrefix := "Inventory.loadYaml()"
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%v Read file. Path: %v, Error: %v", logPrefix, path, err)
+	}
+
+	var file inventoryFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return fmt.Errorf("%v Unmarshal. Path: %v, Error: %v", logPrefix, path, err)
+	}
+
+	// devices reference profiles and credentials
+	for name, profile := range file.Profiles {
+		o.PutProfile(name, profile)
+	}
+
+	for name, credential := range file.Credentials {
+		o.PutCredential(name, credential)
+	}
+
+	for _, device := range file.Devices {
+		if err := o.Put(device); err != nil {
+			return fmt.Errorf("%v Path: %v, Error: %v", logPrefix, path, err)
+		}
+	}
+
+	glog.Infof("%v Path: %v, Profiles: %v, Credentials: %v, Devices: %v",
+		logPrefix, path, len(file.Profiles), len(file.Credentials), len(file.Devices))
+
+	return nil
+}
+
+func (o *Inventory) loadCsv(path string) error {
+	logPrefix := "Inventory.loadCsv()"
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%v Open file. Path: %v, Error: %v", logPrefix, path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = len(csvColumns)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%v Read header. Path: %v, Error: %v", logPrefix, path, err)
+	}
+
+	for idx, column := range csvColumns {
+		if strings.ToLower(header[idx]) != column {
+			return fmt.Errorf("%v Wrong header. Expected: %v, Path: %v", logPrefix, csvColumns, path)
+		}
+	}
+
+	count := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%v Read record. Path: %v, Error: %v", logPrefix, path, err)
+		}
+
+		port := 0
+		if record[2] != "" {
+			port, err = strconv.Atoi(record[2])
+			if err != nil {
+				return fmt.Errorf("%v Wrong port. Path: %v, Device: %v, Error: %v", logPrefix, path, record[0], err)
+			}
+		}
+
+		device := model.Device{
+			Name:       record[0],
+			Host:       record[1],
+			Port:       port,
+			Protocol:   record[3],
+			Profile:    record[4],
+			Credential: record[5],
+			Tags:       splitList(record[6]),
+			Groups:     splitList(record[7]),
+		}
+
+		if err := o.Put(device); err != nil {
+			return fmt.Errorf("%v Path: %v, Error: %v", logPrefix, path, err)
+		}
+		count++
+	}
+
+	glog.Infof("%v Path: %v, Devices: %v", logPrefix, path, count)
+
+	return nil
+}
+
+func splitList(value string) []string {
+	res := []string{}
+	for _, item := range strings.Split(value, CsvListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,46 @@
+package inventory
+
+// Device family settings shared by devices
+type Profile struct {
+	Port int `yaml:"port,omitempty"`
+
+	LoginExpectedString           string `yaml:"loginExpectedString,omitempty"`
+	PasswordExpectedString        string `yaml:"passwordExpectedString,omitempty"`
+	HostnameExpectedString        string `yaml:"hostnameExpectedString,omitempty"`
+	ContinueCommandExpectedString string `yaml:"continueCommandExpectedString,omitempty"`
+	// "regexp" or "literal", service default if empty
+	ExpectedStringsMode string `yaml:"expectedStringsMode,omitempty"`
+
+	EnableCommand                 string `yaml:"enableCommand,omitempty"`
+	EnableExpectedString          string `yaml:"enableExpectedString,omitempty"`
+	EnabledHostnameExpectedString string `yaml:"enabledHostnameExpectedString,omitempty"`
+
+	TerminalType   string `yaml:"terminalType,omitempty"`
+	TerminalWidth  int    `yaml:"terminalWidth,omitempty"`
+	TerminalHeight int    `yaml:"terminalHeight,omitempty"`
+
+	// Charset of device output, e.g. "GBK". utf-8 if empty
+	Charset string `yaml:"charset,omitempty"`
+
+	// Command which shows device configuration, e.g. "show running-config"
+	BackupCommand string `yaml:"backupCommand,omitempty"`
+
+	// Enter and exit config mode, e.g. "configure terminal" and "end"
+	ConfigCommand     string `yaml:"configCommand,omitempty"`
+	ConfigExitCommand string `yaml:"configExitCommand,omitempty"`
+	// Regular expressions of error in command output, e.g. "% Invalid input". Defaults of pusher if empty
+	ConfigErrorStrings []string `yaml:"configErrorStrings,omitempty"`
+	// Regular expressions of lines shown by backup command which are not config commands.
+	// Added to defaults, e.g. "Building configuration" and "end"
+	ConfigIgnoreStrings []string `yaml:"configIgnoreStrings,omitempty"`
+	// Native rollback. Checkpoint is created before change, rollback is run after failed change
+	CheckpointCommand string `yaml:"checkpointCommand,omitempty"`
+	RollbackCommand   string `yaml:"rollbackCommand,omitempty"`
+}
+
+// Secrets referenced by devices. Never returned by API
+type Credential struct {
+	Login          string `yaml:"login"`
+	Password       string `yaml:"password"`
+	EnablePassword string `yaml:"enablePassword,omitempty"`
+}
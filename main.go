@@ -3,14 +3,31 @@ package main
 import (
 	"flag"
 	"fmt"
+	"github.com/deminds/CmdProxy/backup"
+	"github.com/deminds/CmdProxy/configpush"
 	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/grpcapi"
+	"github.com/deminds/CmdProxy/inventory"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/outputs"
+	"github.com/deminds/CmdProxy/parser"
+	"github.com/deminds/CmdProxy/recording"
+	"github.com/deminds/CmdProxy/scheduler"
 	"github.com/deminds/CmdProxy/session"
+	"github.com/deminds/CmdProxy/session/types"
+	"github.com/deminds/CmdProxy/webhook"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"runtime/debug"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/deminds/CmdProxy/controller"
 	"github.com/golang/glog"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -20,11 +37,57 @@ const (
 var (
 	HttpHost = flag.String("host", "0.0.0.0", "IP for start application on it")
 	HttpPort = flag.Int("port", 25505, "Port for start application on it")
+	GrpcPort = flag.Int("grpc-port", 0, "Port for gRPC API, e.g. 25506. gRPC is disabled if 0")
 
 	sessionTimeoutSec = flag.Int("timeout", 10, "Set timeout for session and timeout for command in session")
+
+	consoleRunAs  = flag.String("console-run-as", "", "Comma separated uid:gid pairs allowed to run console commands as. Run-as is disabled if empty")
+	consoleLimits = flag.String("console-limits", "", "Yaml file with resource limits of console commands by run-as user. Not limited if empty")
+	consoleGrace  = flag.Duration("console-kill-grace", 5*time.Second, "Time between SIGTERM and SIGKILL of console command stopped by timeout, cancel, disconnect or shutdown")
+
+	telnetTerminalType   = flag.String("telnet-term", "vt100", "Terminal type sent to telnet devices (TERMINAL-TYPE)")
+	telnetTerminalWidth  = flag.Int("telnet-width", 512, "Terminal width sent to telnet devices (NAWS)")
+	telnetTerminalHeight = flag.Int("telnet-height", 0, "Terminal height sent to telnet devices (NAWS). 0 - disable paging on most devices")
+
+	telnetExpectedStrings = flag.String("telnet-expected-strings", model.ExpectedStringsRegexp, "Mode of telnet expected strings for requests without expectedStringsMode: regexp or literal (text matched as before regexp support)")
+
+	telnetReadLimit = flag.Int64("telnet-max-read-bytes", 64*1024*1024, "Max bytes of telnet command output kept while reading. The rest is read till prompt and dropped. 0 - unlimited")
+
+	telnetDeviceConnections = flag.Int("telnet-device-connections", 1, "Max connections to one device for shared telnet sessions")
+
+	broadcastConcurrency = flag.Int("broadcast-concurrency", 20, "Max devices processed at the same time by broadcast")
+
+	inventoryFiles = flag.String("inventory", "", "Comma separated list of inventory files (yaml or csv)")
+
+	backupDir         = flag.String("backup-dir", "", "Dir for device config backups. Backup is disabled if empty")
+	backupInterval    = flag.Duration("backup-interval", 0, "Interval of scheduled backup. Scheduled backup is disabled if 0")
+	backupTags        = flag.String("backup-tags", "", "Comma separated tags of devices for scheduled backup. All devices if tags and groups are empty")
+	backupGroups      = flag.String("backup-groups", "", "Comma separated groups of devices for scheduled backup")
+	backupConcurrency = flag.Int("backup-concurrency", 10, "Max devices backed up at the same time")
+
+	recordingDir       = flag.String("recording-dir", "", "Dir for session recordings (asciicast v2). Recording is disabled if empty")
+	recordingAll       = flag.Bool("recording-all", false, "Record all sessions. Otherwise only sessions requested with record")
+	recordingMaxBytes  = flag.Int64("recording-max-bytes", 10*1024*1024, "Max size of one recording. Recording stops when limit is reached. 0 - unlimited")
+	recordingRetention = flag.Duration("recording-retention", 7*24*time.Hour, "Recordings older than retention are removed. 0 - keep forever")
+
+	outputMaxBytes  = flag.Int64("output-max-bytes", 10*1024*1024, "Max bytes of command output in response. Longer output is truncated. 0 - unlimited")
+	outputDir       = flag.String("output-dir", "", "Private dir (mode 0700) for whole outputs of truncated results, read by pages. Outputs are not kept if empty")
+	outputRetention = flag.Duration("output-retention", time.Hour, "Kept outputs older than retention are removed. 0 - keep forever")
+
+	jobKeepResults = flag.Int("job-keep-results", 10, "Default number of the last runs kept for scheduled job")
+
+	templatesDir = flag.String("templates-dir", "", "Dir with output parsing templates (*.textfsm)")
+
+	webhookWorkers     = flag.Int("webhook-workers", 4, "Number of webhook deliveries at the same time")
+	webhookAttempts    = flag.Int("webhook-attempts", 5, "Max delivery attempts of webhook event")
+	webhookBackoff     = flag.Duration("webhook-backoff", time.Second, "Delay before the second delivery attempt, doubled for every next one")
+	webhookDeadLetters = flag.String("webhook-dead-letter", "", "File for events not delivered after all attempts (json lines). Only logged if empty")
 )
 
 func main() {
+	// service binary is started by console sessions as helper of command
+	types.RunConsoleExec()
+
 	defer func() {
 		if r := recover(); r != nil {
 			glog.Errorf("main()\n%+v", r, debug.Stack())
@@ -42,20 +105,192 @@ func main() {
 
 	h := http.NewServeMux()
 
-	httpController := controller.NewHttpController(pool, idGenerator, *sessionTimeoutSec)
+	telnetTerminal := types.TelnetTerminal{
+		Type:   *telnetTerminalType,
+		Width:  *telnetTerminalWidth,
+		Height: *telnetTerminalHeight,
+	}
+
+	deviceInventory := inventory.NewInventory()
+	for _, path := range splitFlagList(*inventoryFiles) {
+		if err := deviceInventory.LoadFile(path); err != nil {
+			glog.Fatalf("Load inventory. Error: %v", err)
+		}
+	}
+
+	templates := parser.NewRegistry()
+	if *templatesDir != "" {
+		if err := templates.LoadDir(*templatesDir); err != nil {
+			glog.Fatalf("Load templates. Error: %v", err)
+		}
+	}
+
+	dispatcher := webhook.NewDispatcher(idGenerator, *webhookWorkers, *webhookAttempts, *webhookBackoff, *webhookDeadLetters)
+	dispatcher.Start()
+
+	telnetDevicePool := types.NewTelnetDevicePool(idGenerator, *sessionTimeoutSec, *telnetDeviceConnections, dispatcher)
+
+	var recordings *recording.Store
+	if *recordingDir != "" {
+		var err error
+		recordings, err = recording.NewStore(*recordingDir, *recordingMaxBytes, *recordingRetention, *recordingAll)
+		if err != nil {
+			glog.Fatalf("Create recording store. Error: %v", err)
+		}
+
+		recordings.StartCleanup()
+	}
+
+	telnetFactory := types.NewTelnetSessionFactory(idGenerator, *sessionTimeoutSec, telnetTerminal, telnetDevicePool, recordings, dispatcher)
+
+	consoleCredentials := []types.ConsoleCredential{}
+	for _, value := range splitFlagList(*consoleRunAs) {
+		credential, err := types.ParseConsoleCredential(value)
+		if err != nil {
+			glog.Fatalf("Parse console run-as. Error: %v", err)
+		}
+		consoleCredentials = append(consoleCredentials, credential)
+	}
+
+	var consoleLimitsPolicy *types.ConsoleLimitsPolicy
+	if *consoleLimits != "" {
+		var err error
+		consoleLimitsPolicy, err = types.LoadConsoleLimits(*consoleLimits)
+		if err != nil {
+			glog.Fatalf("Load console limits. Error: %v", err)
+		}
+	}
+
+	types.ConsoleKillGrace = *consoleGrace
+	types.TelnetReadLimit = *telnetReadLimit
+
+	if *telnetExpectedStrings != model.ExpectedStringsRegexp && *telnetExpectedStrings != model.ExpectedStringsLiteral {
+		glog.Fatalf("Wrong telnet expected strings mode: %v", *telnetExpectedStrings)
+	}
+	model.DefaultExpectedStringsMode = *telnetExpectedStrings
+	go stopOnSignal()
+
+	consoleFactory := types.NewConsoleSessionFactory(idGenerator, *sessionTimeoutSec, consoleCredentials, consoleLimitsPolicy, recordings, dispatcher)
+
+	var backuper *backup.Backuper
+	if *backupDir != "" {
+		store, err := backup.NewStore(*backupDir)
+		if err != nil {
+			glog.Fatalf("Create backup store. Error: %v", err)
+		}
+
+		backuper = backup.NewBackuper(deviceInventory, telnetFactory, store, *backupConcurrency)
+
+		if *backupInterval > 0 {
+			backuper.Start(*backupInterval, model.BackupRequest{
+				Tags:   splitFlagList(*backupTags),
+				Groups: splitFlagList(*backupGroups),
+			})
+		}
+	}
+
+	pusher := configpush.NewPusher(deviceInventory, telnetFactory)
+
+	jobScheduler := scheduler.NewScheduler(idGenerator, deviceInventory, consoleFactory, telnetFactory, *jobKeepResults, dispatcher)
+	jobScheduler.Start()
+
+	var outputStore *outputs.Store
+	if *outputDir != "" {
+		var err error
+		outputStore, err = outputs.NewStore(*outputDir, *outputRetention)
+		if err != nil {
+			glog.Fatalf("Create output store. Error: %v", err)
+		}
+
+		outputStore.StartCleanup()
+	}
+
+	httpController := controller.NewHttpController(pool, idGenerator, *sessionTimeoutSec, consoleFactory, telnetFactory, *broadcastConcurrency, deviceInventory, backuper, pusher, recordings, jobScheduler, dispatcher, templates, outputStore, *outputMaxBytes)
 
 	h.HandleFunc(fmt.Sprintf("/api/%v/telnet/connect", API_VERSION), httpController.TelnetConnectHandler)
 	h.HandleFunc(fmt.Sprintf("/api/%v/telnet/list", API_VERSION), httpController.TelnetListHandler)
 	h.HandleFunc(fmt.Sprintf("/api/%v/telnet/disconnect", API_VERSION), httpController.DisconnectHandler)
 	h.HandleFunc(fmt.Sprintf("/api/%v/telnet/command", API_VERSION), httpController.CommandHandler)
+	h.HandleFunc(fmt.Sprintf("/api/%v/telnet/broadcast", API_VERSION), httpController.TelnetBroadcastHandler)
+
+	h.HandleFunc(fmt.Sprintf("/api/%v/inventory/devices", API_VERSION), httpController.InventoryDevicesHandler)
+	h.HandleFunc(fmt.Sprintf("/api/%v/inventory/device", API_VERSION), httpController.InventoryDeviceHandler)
+
+	h.HandleFunc(fmt.Sprintf("/api/%v/backup/run", API_VERSION), httpController.BackupRunHandler)
+	h.HandleFunc(fmt.Sprintf("/api/%v/backup/versions", API_VERSION), httpController.BackupVersionsHandler)
+	h.HandleFunc(fmt.Sprintf("/api/%v/backup/config", API_VERSION), httpController.BackupConfigHandler)
+	h.HandleFunc(fmt.Sprintf("/api/%v/backup/diff", API_VERSION), httpController.BackupDiffHandler)
+
+	h.HandleFunc(fmt.Sprintf("/api/%v/config/push", API_VERSION), httpController.ConfigPushHandler)
+
+	h.HandleFunc(fmt.Sprintf("/api/%v/jobs", API_VERSION), httpController.JobsHandler)
+	h.HandleFunc(fmt.Sprintf("/api/%v/job", API_VERSION), httpController.JobHandler)
+
+	h.HandleFunc(fmt.Sprintf("/api/%v/templates", API_VERSION), httpController.TemplatesHandler)
+	h.HandleFunc(fmt.Sprintf("/api/%v/template", API_VERSION), httpController.TemplateHandler)
+
+	h.HandleFunc(fmt.Sprintf("/api/%v/webhooks", API_VERSION), httpController.WebhooksHandler)
+
+	// path: /api/v1.0/sessions/{id}/recording
+	h.HandleFunc(fmt.Sprintf("/api/%v/sessions/", API_VERSION), httpController.SessionRecordingHandler)
+
+	// path: /api/v1.0/outputs/{id}
+	h.HandleFunc(fmt.Sprintf("/api/%v/outputs/", API_VERSION), httpController.OutputHandler)
 
 	h.HandleFunc(fmt.Sprintf("/api/%v/console/connect", API_VERSION), httpController.ConsoleConnectHandler)
 	h.HandleFunc(fmt.Sprintf("/api/%v/console/list", API_VERSION), httpController.ConsoleListHandler)
 	h.HandleFunc(fmt.Sprintf("/api/%v/console/disconnect", API_VERSION), httpController.DisconnectHandler)
 	h.HandleFunc(fmt.Sprintf("/api/%v/console/command", API_VERSION), httpController.CommandHandler)
+	h.HandleFunc(fmt.Sprintf("/api/%v/console/stdin", API_VERSION), httpController.StdinHandler)
+
+	h.HandleFunc(controller.V2SessionsPath, httpController.V2SessionsHandler)
+	h.HandleFunc(controller.V2SessionsPath+"/", httpController.V2SessionHandler)
+	h.HandleFunc(controller.V2OutputsPath, httpController.OutputHandler)
+	h.HandleFunc(controller.V2OpenApiPath, httpController.V2OpenApiHandler)
+
+	if *GrpcPort != 0 {
+		listener, err := net.Listen("tcp", fmt.Sprintf("%v:%v", *HttpHost, *GrpcPort))
+		if err != nil {
+			glog.Fatalf("Listen gRPC. Error: %v", err)
+		}
+
+		grpcServer := grpc.NewServer()
+		grpcapi.RegisterCmdProxyServer(grpcServer, controller.NewGrpcController(httpController))
+
+		go func() {
+			glog.Infof("Start gRPC listen %v:%v", *HttpHost, *GrpcPort)
+			glog.Fatal(grpcServer.Serve(listener))
+		}()
+	}
 
 	glog.Infof("Start listen %v:%v", *HttpHost, *HttpPort)
 	l := http.ListenAndServe(fmt.Sprintf("%v:%v", *HttpHost, *HttpPort), h)
 
 	glog.Fatal(l)
 }
+
+// Process groups of console commands are stopped before exit, otherwise they outlive service
+func stopOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	sig := <-signals
+	glog.Infof("Received signal %v. Stop console sessions", sig)
+	types.ShutdownConsoleSessions()
+
+	glog.Infoln(">>>>> Service stop")
+	glog.Flush()
+	os.Exit(0)
+}
+
+// Split comma separated flag value. Empty items are skipped
+func splitFlagList(value string) []string {
+	res := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
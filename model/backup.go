@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type BackupRequest struct {
+	// Inventory devices selected by name, tags or groups
+	Devices []string `json:"devices,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Groups  []string `json:"groups,omitempty"`
+}
+
+type BackupResult struct {
+	Device  string `json:"device"`
+	Version string `json:"version,omitempty"`
+	// False if config is the same as in the last version
+	Changed bool   `json:"changed"`
+	Status  Status `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BackupResponse struct {
+	Results []BackupResult `json:"results"`
+}
+
+type BackupVersion struct {
+	Version string    `json:"version"`
+	Time    time.Time `json:"time"`
+	Size    int64     `json:"size"`
+}
+
+type BackupVersionListResponse struct {
+	Device   string          `json:"device"`
+	Versions []BackupVersion `json:"versions"`
+}
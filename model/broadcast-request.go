@@ -0,0 +1,36 @@
+package model
+
+import "github.com/golang/glog"
+
+type BroadcastRequest struct {
+	Targets  []ConnectTelnetRequest `json:"targets"`
+	Commands []string               `json:"commands"`
+
+	// Inventory devices added to targets by name, tags or groups
+	Devices []string `json:"devices,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Groups  []string `json:"groups,omitempty"`
+
+	// Max devices processed at the same time. Service default is used if empty
+	Concurrency int `json:"concurrency,omitempty"`
+	// Stream result of each device as separate json line when device is done
+	Stream bool `json:"stream,omitempty"`
+	// Return telnet output as is, without removing echo, prompt and control sequences
+	Raw bool `json:"raw,omitempty"`
+}
+
+// Should be called after inventory devices are added to targets.
+// Targets are validated one by one, wrong target fails only its result
+func (o *BroadcastRequest) IsValid() bool {
+	if len(o.Targets) == 0 ||
+		len(o.Commands) == 0 ||
+		o.Concurrency < 0 {
+
+		glog.Errorf("BroadcastRequest.IsValid(). Is not valid. Targets: %v, Commands: %v, Concurrency: %v",
+			len(o.Targets), o.Commands, o.Concurrency)
+
+		return false
+	}
+
+	return true
+}
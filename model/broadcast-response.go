@@ -0,0 +1,16 @@
+package model
+
+type BroadcastDeviceResult struct {
+	// Index of target in request
+	Index    int             `json:"index"`
+	Device   string          `json:"device,omitempty"`
+	Host     string          `json:"host"`
+	Port     int             `json:"port"`
+	Status   Status          `json:"status"`
+	Error    string          `json:"error,omitempty"`
+	Commands []CommandResult `json:"commands"`
+}
+
+type BroadcastResponse struct {
+	Results []BroadcastDeviceResult `json:"results"`
+}
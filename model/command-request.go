@@ -1,7 +1,128 @@
 package model
 
+import (
+	"regexp"
+
+	"github.com/golang/glog"
+)
+
+const (
+	OnErrorStop     = "stop"
+	OnErrorContinue = "continue"
+
+	// Output converted from device charset, chars which can not be converted are replaced by U+FFFD
+	OutputEncodingUtf8 = "utf8"
+	// Output bytes as is encoded by base64
+	OutputEncodingBase64 = "base64"
+	// Output bytes as is in http response body instead of json
+	OutputEncodingRaw = "raw"
+)
+
 type CommandRequest struct {
 	SessionId string `json:"sessionid"`
 	CommandId int    `json:"commandid,omitempty"`
-	Command   string `json:"command"`
+	Command   string `json:"command,omitempty"`
+	// Ordered list of commands executed in one call instead of Command
+	Commands []string `json:"commands,omitempty"`
+	// Behaviour of Commands on error: "stop" (default) or "continue"
+	OnError string `json:"onError,omitempty"`
+	// Return telnet output as is, without removing echo, prompt and control sequences
+	Raw bool `json:"raw,omitempty"`
+	// Answers for interactive questions of Command
+	Expect []ExpectStep `json:"expect,omitempty"`
+	// Name of template used to parse output of Command into rows
+	Parse string `json:"parse,omitempty"`
+	// Input of console Command, plain text or base64 by StdinEncoding
+	Stdin         string `json:"stdin,omitempty"`
+	StdinEncoding string `json:"stdinEncoding,omitempty"`
+	// Input of console Command is not closed after Stdin. More input is sent by StdinRequest
+	KeepStdinOpen bool `json:"keepStdinOpen,omitempty"`
+	// "utf8" (default), "base64" or "raw". Raw is supported only for single command without Parse
+	OutputEncoding string `json:"outputEncoding,omitempty"`
+	// Max bytes of output in response, lower than max of service. Limit of service is used if 0
+	MaxOutputBytes int64 `json:"maxOutputBytes,omitempty"`
+}
+
+func (o *CommandRequest) IsValid() bool {
+	if o.SessionId == "" ||
+		(o.Command == "") == (len(o.Commands) == 0) ||
+		(o.OnError != "" && o.OnError != OnErrorStop && o.OnError != OnErrorContinue) {
+
+		glog.Errorf("CommandRequest.IsValid(). Is not valid. Struct: %+v", o.WithoutStdin())
+
+		return false
+	}
+
+	if len(o.Expect) > 0 && len(o.Commands) > 0 {
+		glog.Errorf("CommandRequest.IsValid(). Expect is supported only for single command. Struct: %+v", o.WithoutStdin())
+
+		return false
+	}
+
+	if o.MaxOutputBytes < 0 {
+		glog.Errorf("CommandRequest.IsValid(). MaxOutputBytes should not be negative. Struct: %+v", o.WithoutStdin())
+
+		return false
+	}
+
+	if o.Parse != "" && len(o.Commands) > 0 {
+		glog.Errorf("CommandRequest.IsValid(). Parse is supported only for single command. Struct: %+v", o.WithoutStdin())
+
+		return false
+	}
+
+	if (o.Stdin != "" || o.KeepStdinOpen) && len(o.Commands) > 0 {
+		glog.Errorf("CommandRequest.IsValid(). Stdin is supported only for single command. Struct: %+v", o.WithoutStdin())
+
+		return false
+	}
+
+	if _, err := decodeStdin(o.Stdin, o.StdinEncoding); err != nil {
+		glog.Errorf("CommandRequest.IsValid(). Stdin is not valid. Error: %v", err)
+
+		return false
+	}
+
+	switch o.OutputEncoding {
+	case "", OutputEncodingUtf8, OutputEncodingBase64:
+	case OutputEncodingRaw:
+		if len(o.Commands) > 0 || o.Parse != "" {
+			glog.Errorf("CommandRequest.IsValid(). Raw output is supported only for single command without parse. Struct: %+v", o.WithoutStdin())
+
+			return false
+		}
+	default:
+		glog.Errorf("CommandRequest.IsValid(). Wrong output encoding. Struct: %+v", o.WithoutStdin())
+
+		return false
+	}
+
+	for idx, step := range o.Expect {
+		if _, err := regexp.Compile(step.Expect); err != nil || step.Expect == "" || step.TimeoutSec < 0 {
+			glog.Errorf("CommandRequest.IsValid(). Expect step %v is not valid. Step: %+v, Error: %v", idx, step, err)
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// Copy of request without Stdin for logs and responses. Input may hold secrets
+func (o *CommandRequest) WithoutStdin() CommandRequest {
+	res := *o
+	res.Stdin = ""
+
+	return res
+}
+
+// Decoded Stdin. nil if Stdin is empty. Request should be valid
+func (o *CommandRequest) StdinBytes() []byte {
+	if o.Stdin == "" {
+		return nil
+	}
+
+	data, _ := decodeStdin(o.Stdin, o.StdinEncoding)
+
+	return data
 }
@@ -3,4 +3,17 @@ package model
 type CommandResponse struct {
 	CommandRequest `json:",inline"`
 	Output         string `json:"output"`
+	Prompt         string `json:"prompt,omitempty"`
+	Mode           string `json:"mode,omitempty"`
+	// How console command process ended
+	Exit *ProcessExit `json:"exit,omitempty"`
+	// Chars of output replaced by U+FFFD while output is converted to utf8
+	Replacements int `json:"replacements,omitempty"`
+	// Set if output is cut to max output bytes
+	TruncatedOutput `json:",inline"`
+	// Output parsed by Parse template. ParseError is set if parsing failed
+	Parsed     []map[string]interface{} `json:"parsed,omitempty"`
+	ParseError string                   `json:"parseError,omitempty"`
+	// Results of Commands
+	Results []CommandResult `json:"results,omitempty"`
 }
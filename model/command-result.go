@@ -0,0 +1,17 @@
+package model
+
+// Result of one command in batch or broadcast
+type CommandResult struct {
+	Command string `json:"command"`
+	Output  string `json:"output"`
+	Prompt  string `json:"prompt,omitempty"`
+	Mode    string `json:"mode,omitempty"`
+	Status  Status `json:"status"`
+	Error   string `json:"error,omitempty"`
+	// How console command process ended
+	Exit *ProcessExit `json:"exit,omitempty"`
+	// Chars of output replaced by U+FFFD while output is converted to utf8
+	Replacements int `json:"replacements,omitempty"`
+	// Set if output is cut to max output bytes
+	TruncatedOutput `json:",inline"`
+}
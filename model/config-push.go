@@ -0,0 +1,46 @@
+package model
+
+import "github.com/golang/glog"
+
+const (
+	RollbackNone     = "none"
+	RollbackSnapshot = "snapshot"
+	RollbackNative   = "native"
+)
+
+type ConfigPushRequest struct {
+	// Inventory device
+	Device string   `json:"device"`
+	Lines  []string `json:"lines"`
+	// Rollback on error: "none" (default), "snapshot" - negate lines added to config shown
+	// before change and add removed ones, "native" - rollback command of device profile
+	Rollback string `json:"rollback,omitempty"`
+}
+
+func (o *ConfigPushRequest) IsValid() bool {
+	if o.Device == "" ||
+		len(o.Lines) == 0 ||
+		(o.Rollback != "" && o.Rollback != RollbackNone && o.Rollback != RollbackSnapshot && o.Rollback != RollbackNative) {
+
+		glog.Errorf("ConfigPushRequest.IsValid(). Is not valid. Struct: %+v", o)
+
+		return false
+	}
+
+	return true
+}
+
+type ConfigPushResponse struct {
+	Device string `json:"device"`
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Lines sent to device. The last one is failed if status is ERROR
+	Applied    []CommandResult `json:"applied"`
+	NotApplied []string        `json:"notApplied"`
+
+	Rollback      string `json:"rollback,omitempty"`
+	RolledBack    bool   `json:"rolledBack"`
+	RollbackError string `json:"rollbackError,omitempty"`
+	// Lines sent by snapshot rollback
+	RollbackLines []string `json:"rollbackLines,omitempty"`
+}
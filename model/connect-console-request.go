@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+const (
+	EnvModeExtend  = "extend"
+	EnvModeReplace = "replace"
+)
+
+type ConnectConsoleRequest struct {
+	// Record session transcript. Requires recording to be enabled in service
+	Record bool `json:"record,omitempty"`
+
+	ConsoleOptions
+}
+
+// Process settings of console commands. Service process settings are used if empty
+type ConsoleOptions struct {
+	// Run commands as user. Uid and Gid should be set together and allowed by service
+	Uid *uint32 `json:"uid,omitempty"`
+	Gid *uint32 `json:"gid,omitempty"`
+	// Working dir of commands
+	Dir string `json:"dir,omitempty"`
+	// Environment variables. EnvMode: "extend" (default) - added to service environment, "replace" - only Env is used
+	Env     map[string]string `json:"env,omitempty"`
+	EnvMode string            `json:"envMode,omitempty"`
+	// Octal file mode creation mask, e.g. "027"
+	Umask string `json:"umask,omitempty"`
+}
+
+func (o *ConsoleOptions) IsValid() bool {
+	if (o.Uid == nil) != (o.Gid == nil) ||
+		(o.EnvMode != "" && o.EnvMode != EnvModeExtend && o.EnvMode != EnvModeReplace) {
+
+		glog.Errorf("ConsoleOptions.IsValid(). Is not valid. Uid: %v, Gid: %v, EnvMode: %v", o.Uid != nil, o.Gid != nil, o.EnvMode)
+
+		return false
+	}
+
+	for name := range o.Env {
+		if name == "" || strings.Contains(name, "=") {
+			glog.Errorf("ConsoleOptions.IsValid(). Wrong environment variable name: '%v'", name)
+
+			return false
+		}
+	}
+
+	if _, err := o.UmaskValue(); err != nil {
+		glog.Errorf("ConsoleOptions.IsValid(). Wrong umask: '%v'. Error: %v", o.Umask, err)
+
+		return false
+	}
+
+	return true
+}
+
+// Parsed Umask. -1 if Umask is empty
+func (o *ConsoleOptions) UmaskValue() (int, error) {
+	if o.Umask == "" {
+		return -1, nil
+	}
+
+	umask, err := strconv.ParseUint(o.Umask, 8, 32)
+	if err != nil || umask > 0777 {
+		return -1, fmt.Errorf("umask should be octal number from 0 to 0777")
+	}
+
+	return int(umask), nil
+}
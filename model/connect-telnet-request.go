@@ -1,8 +1,26 @@
 package model
 
-import "github.com/golang/glog"
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/golang/glog"
+
+	"github.com/deminds/CmdProxy/charset"
+)
+
+const (
+	ExpectedStringsRegexp  = "regexp"
+	ExpectedStringsLiteral = "literal"
+)
+
+// Mode of requests without ExpectedStringsMode. Set by service before requests are handled
+var DefaultExpectedStringsMode = ExpectedStringsRegexp
 
 type ConnectTelnetRequest struct {
+	// Name of inventory device. Settings of device are defaults for fields below
+	Device string `json:"device,omitempty"`
+
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
 	Login    string `json:"login"`
@@ -12,21 +30,119 @@ type ConnectTelnetRequest struct {
 	PasswordExpectedString        string `json:"passwordExpectedString"`
 	HostnameExpectedString        string `json:"hostnameExpectedString"`
 	ContinueCommandExpectedString string `json:"continueCommandExpectedString"`
+	// "regexp" or "literal". Literal expected strings are matched as text like before regexp support.
+	// DefaultExpectedStringsMode if empty
+	ExpectedStringsMode string `json:"expectedStringsMode,omitempty"`
+
+	// Optional terminal settings negotiated via TERMINAL-TYPE and NAWS. Service defaults are used if empty
+	TerminalType   string `json:"terminalType,omitempty"`
+	TerminalWidth  int    `json:"terminalWidth,omitempty"`
+	TerminalHeight int    `json:"terminalHeight,omitempty"`
+
+	// Share authenticated connection with other sessions of the same host, port and login.
+	// MaxConnections limits concurrent connections to device, service default is used if empty
+	Shared         bool `json:"shared,omitempty"`
+	MaxConnections int  `json:"maxConnections,omitempty"`
+
+	// Charset of device output converted to utf8, e.g. "latin1" or "GBK". utf-8 if empty
+	Charset string `json:"charset,omitempty"`
+
+	// Record session transcript. Requires recording to be enabled in service
+	Record bool `json:"record,omitempty"`
+
+	// Optional privileged mode escalation. Will be done on connect if EnablePassword is set
+	EnablePassword       string `json:"enablePassword,omitempty"`
+	EnableCommand        string `json:"enableCommand,omitempty"`
+	EnableExpectedString string `json:"enableExpectedString,omitempty"`
+	// Prompt expected after escalation. If empty prompt should look like privileged mode (ends with '#')
+	EnabledHostnameExpectedString string `json:"enabledHostnameExpectedString,omitempty"`
 }
 
 func (o *ConnectTelnetRequest) IsValid() bool {
-	if o.Host == "" ||
-		o.Port == 0 ||
-		o.Login == "" ||
-		o.Password == "" ||
-		o.LoginExpectedString == "" ||
-		o.PasswordExpectedString == "" ||
-		o.HostnameExpectedString == "" {
+	required := []struct {
+		field string
+		empty bool
+	}{
+		{"Host", o.Host == ""},
+		{"Port", o.Port == 0},
+		{"Login", o.Login == ""},
+		{"Password", o.Password == ""},
+		{"LoginExpectedString", o.LoginExpectedString == ""},
+		{"PasswordExpectedString", o.PasswordExpectedString == ""},
+		{"HostnameExpectedString", o.HostnameExpectedString == ""},
+	}
+
+	for _, r := range required {
+		if r.empty {
+			o.logInvalid("Field is empty", r.field, "")
+
+			return false
+		}
+	}
+
+	if o.MaxConnections < 0 {
+		o.logInvalid("Wrong max connections", "MaxConnections", o.MaxConnections)
+
+		return false
+	}
+
+	if !charset.IsSupported(o.Charset) {
+		o.logInvalid("Charset is not supported", "Charset", o.Charset)
+
+		return false
+	}
+
+	// NAWS sends width and height as 16 bit values
+	if o.TerminalWidth < 0 || o.TerminalWidth > 0xffff ||
+		o.TerminalHeight < 0 || o.TerminalHeight > 0xffff {
+
+		o.logInvalid("Wrong terminal size", "TerminalWidth x TerminalHeight", fmt.Sprintf("%vx%v", o.TerminalWidth, o.TerminalHeight))
+
+		return false
+	}
+
+	if o.ExpectedStringsMode != "" &&
+		o.ExpectedStringsMode != ExpectedStringsRegexp &&
+		o.ExpectedStringsMode != ExpectedStringsLiteral {
 
-		glog.Errorf("ConnectTelnetRequest.IsValid(). Is not valid. Struct: %+v", o)
+		o.logInvalid("Wrong expected strings mode", "ExpectedStringsMode", o.ExpectedStringsMode)
 
 		return false
 	}
 
+	// any text is valid literal expected string
+	if o.LiteralExpectedStrings() {
+		return true
+	}
+
+	for _, expr := range []string{
+		o.LoginExpectedString,
+		o.PasswordExpectedString,
+		o.HostnameExpectedString,
+		o.ContinueCommandExpectedString,
+		o.EnableExpectedString,
+		o.EnabledHostnameExpectedString} {
+
+		if _, err := regexp.Compile(expr); err != nil {
+			glog.Errorf("ConnectTelnetRequest.IsValid(). Is not valid regexp: '%v'. Error: %v", expr, err)
+
+			return false
+		}
+	}
+
 	return true
 }
+
+func (o *ConnectTelnetRequest) LiteralExpectedStrings() bool {
+	if o.ExpectedStringsMode == "" {
+		return DefaultExpectedStringsMode == ExpectedStringsLiteral
+	}
+
+	return o.ExpectedStringsMode == ExpectedStringsLiteral
+}
+
+// Only address of device and failed field are logged, passwords are not
+func (o *ConnectTelnetRequest) logInvalid(reason string, field string, value interface{}) {
+	glog.Errorf("ConnectTelnetRequest.IsValid(). %v. Host: %v, Port: %v, Login: %v, Field: %v, Value: '%v'",
+		reason, o.Host, o.Port, o.Login, field, value)
+}
@@ -0,0 +1,5 @@
+package model
+
+type DeviceListResponse struct {
+	Devices []Device `json:"devices"`
+}
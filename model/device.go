@@ -0,0 +1,33 @@
+package model
+
+import "github.com/golang/glog"
+
+const (
+	ProtocolTelnet = "telnet"
+)
+
+// Inventory device. Connect settings are resolved from profile and credential by name
+type Device struct {
+	Name       string   `json:"name" yaml:"name"`
+	Host       string   `json:"host" yaml:"host"`
+	Port       int      `json:"port,omitempty" yaml:"port,omitempty"`
+	Protocol   string   `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Profile    string   `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Credential string   `json:"credential,omitempty" yaml:"credential,omitempty"`
+	Tags       []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Groups     []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+func (o *Device) IsValid() bool {
+	if o.Name == "" ||
+		o.Host == "" ||
+		o.Port < 0 ||
+		(o.Protocol != "" && o.Protocol != ProtocolTelnet) {
+
+		glog.Errorf("Device.IsValid(). Is not valid. Struct: %+v", o)
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,10 @@
+package model
+
+type ExpectStep struct {
+	// Regular expression of device question
+	Expect string `json:"expect"`
+	// Answer sent when question is matched
+	Send string `json:"send"`
+	// Session timeout is used if empty
+	TimeoutSec int `json:"timeoutSec,omitempty"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "github.com/golang/glog"
+
+type ParseTemplate struct {
+	// e.g. cisco_ios_show_interfaces
+	Name string `json:"name"`
+	// TextFSM template
+	Template string `json:"template"`
+}
+
+func (o *ParseTemplate) IsValid() bool {
+	if o.Name == "" || o.Template == "" {
+		glog.Errorf("ParseTemplate.IsValid(). Is not valid. Struct: %+v", o)
+
+		return false
+	}
+
+	return true
+}
+
+type ParseTemplateListResponse struct {
+	Templates []string `json:"templates"`
+}
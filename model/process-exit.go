@@ -0,0 +1,22 @@
+package model
+
+// Reason of console command end
+const (
+	ExitReasonExited     = "exited"
+	ExitReasonSignaled   = "signaled"
+	ExitReasonTimeout    = "timeout"
+	ExitReasonCanceled   = "canceled"
+	ExitReasonDisconnect = "disconnect"
+	ExitReasonShutdown   = "shutdown"
+	ExitReasonOutput     = "outputLimit"
+)
+
+// How console command process ended. Process group is killed by SIGTERM and then by
+// SIGKILL for every reason except exited and signaled
+type ProcessExit struct {
+	Reason string `json:"reason"`
+	// -1 if process is killed by signal
+	Code int `json:"code"`
+	// Signal which killed process, e.g. "killed"
+	Signal string `json:"signal,omitempty"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	JobTargetConsole = "console"
+	JobTargetDevice  = "device"
+)
+
+type ScheduledJob struct {
+	// Generated on create
+	Id   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// "console" or "device" (inventory device)
+	Target   string   `json:"target"`
+	Device   string   `json:"device,omitempty"`
+	Commands []string `json:"commands"`
+	// Standard 5 fields cron expression: minute hour day-of-month month day-of-week
+	Cron string `json:"cron"`
+	Raw  bool   `json:"raw,omitempty"`
+	// Number of last runs kept, service default is used if empty
+	KeepResults int `json:"keepResults,omitempty"`
+	// Run result is POSTed to url if set
+	Webhook string `json:"webhook,omitempty"`
+}
+
+func (o *ScheduledJob) IsValid() bool {
+	if (o.Target != JobTargetConsole && o.Target != JobTargetDevice) ||
+		(o.Target == JobTargetDevice && o.Device == "") ||
+		len(o.Commands) == 0 ||
+		o.Cron == "" ||
+		o.KeepResults < 0 {
+
+		glog.Errorf("ScheduledJob.IsValid(). Is not valid. Struct: %+v", o)
+
+		return false
+	}
+
+	return true
+}
+
+type JobRun struct {
+	JobId    string          `json:"jobId"`
+	Started  time.Time       `json:"started"`
+	Finished time.Time       `json:"finished"`
+	Status   Status          `json:"status"`
+	Error    string          `json:"error,omitempty"`
+	Results  []CommandResult `json:"results"`
+}
+
+type JobResponse struct {
+	ScheduledJob
+	// The last runs, oldest first
+	Runs []JobRun `json:"runs"`
+}
+
+type JobListResponse struct {
+	Jobs []ScheduledJob `json:"jobs"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "github.com/golang/glog"
+
+const (
+	SessionTypeConsole = "console"
+	SessionTypeTelnet  = "telnet"
+)
+
+// Body of POST /api/v2/sessions
+type SessionCreateRequest struct {
+	// "console" or "telnet"
+	Type string `json:"type"`
+	// Telnet connection parameters. Only Record is used by console
+	ConnectTelnetRequest
+	// Process settings of console commands
+	ConsoleOptions
+}
+
+func (o *SessionCreateRequest) IsValid() bool {
+	if o.Type != SessionTypeConsole && o.Type != SessionTypeTelnet {
+		glog.Errorf("SessionCreateRequest.IsValid(). Is not valid. Type: %v", o.Type)
+
+		return false
+	}
+
+	return true
+}
+
+type SessionResource struct {
+	Id     string `json:"id"`
+	Type   string `json:"type"`
+	Closed bool   `json:"closed"`
+}
+
+type SessionListResponse struct {
+	Sessions []SessionResource `json:"sessions"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/golang/glog"
+)
+
+const (
+	StdinEncodingText   = "text"
+	StdinEncodingBase64 = "base64"
+)
+
+// More input of console command started with KeepStdinOpen
+type StdinRequest struct {
+	SessionId string `json:"sessionid"`
+	// Plain text or base64 by StdinEncoding
+	Stdin         string `json:"stdin,omitempty"`
+	StdinEncoding string `json:"stdinEncoding,omitempty"`
+	// Close input after Stdin, command reads end of file
+	Close bool `json:"close,omitempty"`
+	// Stdin is password or other secret, it is masked in session recording
+	Secret bool `json:"secret,omitempty"`
+}
+
+func (o *StdinRequest) IsValid() bool {
+	if o.SessionId == "" || (o.Stdin == "" && !o.Close) {
+		glog.Errorf("StdinRequest.IsValid(). Is not valid. SessionId: %v, Close: %v", o.SessionId, o.Close)
+
+		return false
+	}
+
+	if _, err := decodeStdin(o.Stdin, o.StdinEncoding); err != nil {
+		glog.Errorf("StdinRequest.IsValid(). Stdin is not valid. SessionId: %v, Error: %v", o.SessionId, err)
+
+		return false
+	}
+
+	return true
+}
+
+// Decoded Stdin. Request should be valid
+func (o *StdinRequest) StdinBytes() []byte {
+	data, _ := decodeStdin(o.Stdin, o.StdinEncoding)
+
+	return data
+}
+
+// Plain text is used if encoding is empty
+func decodeStdin(stdin string, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", StdinEncodingText:
+		return []byte(stdin), nil
+	case StdinEncodingBase64:
+		return base64.StdEncoding.DecodeString(stdin)
+	default:
+		return nil, fmt.Errorf("stdinEncoding should be %v or %v", StdinEncodingText, StdinEncodingBase64)
+	}
+}
@@ -0,0 +1,11 @@
+package model
+
+// Output is cut to max output bytes. Whole output of OutputSize bytes is kept by OutputId
+// and is read by pages. OutputId is empty if output store is disabled or output is not saved
+type TruncatedOutput struct {
+	Truncated  bool   `json:"truncated,omitempty"`
+	OutputSize int64  `json:"outputSize,omitempty"`
+	OutputId   string `json:"outputId,omitempty"`
+	// Output is cut by read limit of session while it is read. The rest is dropped and is not kept
+	ReadLimitReached bool `json:"readLimitReached,omitempty"`
+}
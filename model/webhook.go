@@ -0,0 +1,86 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+type EventType string
+
+const (
+	EventSessionConnected EventType = "session.connected"
+	EventSessionClosed    EventType = "session.closed"
+	// session dropped by timeout between commands
+	EventSessionTimeout  EventType = "session.timeout"
+	EventCommandFailed   EventType = "command.failed"
+	EventPolicyViolation EventType = "policy.violation"
+	EventJobFinished     EventType = "job.finished"
+)
+
+var eventTypes = map[EventType]bool{
+	EventSessionConnected: true,
+	EventSessionClosed:    true,
+	EventSessionTimeout:   true,
+	EventCommandFailed:    true,
+	EventPolicyViolation:  true,
+	EventJobFinished:      true,
+}
+
+type Webhook struct {
+	// Generated on create
+	Id  string `json:"id"`
+	Url string `json:"url"`
+	// Body is signed by HMAC-SHA256 with secret if set
+	Secret string `json:"secret,omitempty"`
+	// Subscribed events. All events if empty
+	Events []EventType `json:"events,omitempty"`
+}
+
+func (o *Webhook) IsValid() bool {
+	isValid := strings.HasPrefix(o.Url, "http://") || strings.HasPrefix(o.Url, "https://")
+	for _, event := range o.Events {
+		isValid = isValid && eventTypes[event]
+	}
+
+	if !isValid {
+		glog.Errorf("Webhook.IsValid(). Is not valid. Url: %v, Events: %v", o.Url, o.Events)
+
+		return false
+	}
+
+	return true
+}
+
+func (o *Webhook) IsSubscribed(eventType EventType) bool {
+	if len(o.Events) == 0 {
+		return true
+	}
+
+	for _, event := range o.Events {
+		if event == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WebhookEvent struct {
+	// Generated on notify
+	Id          string    `json:"id"`
+	Type        EventType `json:"type"`
+	Time        time.Time `json:"time"`
+	SessionId   string    `json:"sessionId,omitempty"`
+	SessionType string    `json:"sessionType,omitempty"`
+	Host        string    `json:"host,omitempty"`
+	Port        int       `json:"port,omitempty"`
+	Command     string    `json:"command,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Job         *JobRun   `json:"job,omitempty"`
+}
+
+type WebhookListResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	Version = "3.0.3"
+
+	componentsPrefix = "#/components/schemas/"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schemas of models generated from json tags. Struct types are put to components and referenced by name
+type Schemas struct {
+	components map[string]interface{}
+}
+
+func NewSchemas() *Schemas {
+	return &Schemas{
+		components: map[string]interface{}{},
+	}
+}
+
+func (o *Schemas) Components() map[string]interface{} {
+	return o.components
+}
+
+// Reference to schema of value type
+func (o *Schemas) Ref(value interface{}) map[string]interface{} {
+	return o.schema(reflect.TypeOf(value))
+}
+
+func (o *Schemas) schema(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		return o.schema(t.Elem())
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": o.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": o.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return o.object(t)
+		}
+
+		if _, ok := o.components[t.Name()]; !ok {
+			// placeholder stops recursion of self referencing types
+			o.components[t.Name()] = nil
+			o.components[t.Name()] = o.object(t)
+		}
+
+		return map[string]interface{}{"$ref": componentsPrefix + t.Name()}
+	default:
+		// interface{}: any value
+		return map[string]interface{}{}
+	}
+}
+
+func (o *Schemas) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	o.addProperties(t, properties)
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+// Fields of embedded structs are added as fields of parent like encoding/json does
+func (o *Schemas) addProperties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+
+			if fieldType.Kind() == reflect.Struct {
+				o.addProperties(fieldType, properties)
+
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = o.schema(field.Type)
+	}
+}
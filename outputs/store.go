@@ -0,0 +1,177 @@
+package outputs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	FileExt = ".out"
+
+	cleanupInterval = time.Minute
+	// random bytes of output id
+	outputIdBytes = 16
+
+	// Max page returned by Read, it is kept in memory. Fits default max message of gRPC
+	MaxPageBytes = 1 << 20
+)
+
+var outputIdRegexp = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// Dir should not be accessible by other users: outputs may hold device configs
+func NewStore(dir string, retention time.Duration) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("NewStore() Create dir. Dir: %v, Error: %v", dir, err)
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("NewStore() Stat dir. Dir: %v, Error: %v", dir, err)
+	}
+	if !info.IsDir() || info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("NewStore() Dir should not be symlink and should be accessible only by owner. Dir: %v, Mode: %v", dir, info.Mode())
+	}
+
+	return &Store{
+		dir:       dir,
+		retention: retention,
+	}, nil
+}
+
+// Keep whole outputs of truncated command results as <dir>/<output id>.out.
+// Output id is random, so it can not be guessed by clients of other sessions
+type Store struct {
+	dir       string
+	retention time.Duration
+}
+
+// Save output and return its id
+func (o *Store) Save(output string) (string, error) {
+	logPrefix := "Store.Save()"
+
+	idBytes := make([]byte, outputIdBytes)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", fmt.Errorf("%v Generate id. Error: %v", logPrefix, err)
+	}
+	id := hex.EncodeToString(idBytes)
+
+	path := filepath.Join(o.dir, id+FileExt)
+	if err := ioutil.WriteFile(path, []byte(output), 0600); err != nil {
+		return "", fmt.Errorf("%v Write file. Path: %v, Error: %v", logPrefix, path, err)
+	}
+
+	glog.Infof("%v Output saved. ID: %v, Size: %v", logPrefix, id, len(output))
+
+	return id, nil
+}
+
+// Open output for reading by pages. Caller should close file
+func (o *Store) Open(id string) (*os.File, int64, error) {
+	logPrefix := "Store.Open()"
+
+	if !outputIdRegexp.MatchString(id) {
+		return nil, 0, fmt.Errorf("%v Wrong output id: '%v'", logPrefix, id)
+	}
+
+	file, err := os.Open(filepath.Join(o.dir, id+FileExt))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%v Output not found. ID: %v, Error: %v", logPrefix, id, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, 0, fmt.Errorf("%v Stat. ID: %v, Error: %v", logPrefix, id, err)
+	}
+
+	return file, info.Size(), nil
+}
+
+// Read page of output from offset. Page is shorter than limit at the end of output and
+// is at most MaxPageBytes, also if limit is 0. Size of whole output is returned
+func (o *Store) Read(id string, offset int64, limit int64) ([]byte, int64, error) {
+	file, size, err := o.Open(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	length, err := PageLength(size, offset, limit)
+	if err != nil {
+		return nil, size, fmt.Errorf("Store.Read() ID: %v, Error: %v", id, err)
+	}
+	if length > MaxPageBytes {
+		length = MaxPageBytes
+	}
+
+	page := make([]byte, length)
+	if _, err := io.ReadFull(io.NewSectionReader(file, offset, length), page); err != nil {
+		return nil, size, fmt.Errorf("Store.Read() Read file. ID: %v, Error: %v", id, err)
+	}
+
+	return page, size, nil
+}
+
+// Length of page from offset of output of size. Page is to the end of output if limit is 0
+func PageLength(size int64, offset int64, limit int64) (int64, error) {
+	if offset < 0 || offset > size || limit < 0 {
+		return 0, fmt.Errorf("wrong page. Offset: %v, Limit: %v, Size: %v", offset, limit, size)
+	}
+
+	if limit == 0 || limit > size-offset {
+		return size - offset, nil
+	}
+
+	return limit, nil
+}
+
+// Remove outputs older than retention periodically
+func (o *Store) StartCleanup() {
+	if o.retention <= 0 {
+		return
+	}
+
+	go func() {
+		for {
+			o.cleanup()
+			time.Sleep(cleanupInterval)
+		}
+	}()
+}
+
+func (o *Store) cleanup() {
+	logPrefix := "Store.cleanup()"
+
+	files, err := ioutil.ReadDir(o.dir)
+	if err != nil {
+		glog.Errorf("%v Read dir. Dir: %v, Error: %v", logPrefix, o.dir, err)
+
+		return
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), FileExt) || time.Since(file.ModTime()) < o.retention {
+			continue
+		}
+
+		path := filepath.Join(o.dir, file.Name())
+		if err := os.Remove(path); err != nil {
+			glog.Errorf("%v Remove. Path: %v, Error: %v", logPrefix, path, err)
+
+			continue
+		}
+
+		glog.Infof("%v Removed. Path: %v", logPrefix, path)
+	}
+}
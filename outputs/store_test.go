@@ -0,0 +1,51 @@
+package outputs
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStoreReadPage(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "outputs"), 0)
+	if err != nil {
+		t.Fatalf("NewStore(). Error: %v", err)
+	}
+
+	output := strings.Repeat("x", MaxPageBytes) + "tail"
+	id, err := store.Save(output)
+	if err != nil {
+		t.Fatalf("Save(). Error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		offset   int64
+		limit    int64
+		expected int
+	}{
+		{name: "page", offset: 10, limit: 100, expected: 100},
+		{name: "end of output", offset: int64(len(output)) - 2, limit: 100, expected: 2},
+		{name: "rest is capped", offset: 1, limit: 0, expected: MaxPageBytes},
+		{name: "limit is capped", offset: 0, limit: 2 * MaxPageBytes, expected: MaxPageBytes},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, size, err := store.Read(id, test.offset, test.limit)
+			if err != nil {
+				t.Fatalf("Read(). Error: %v", err)
+			}
+			if size != int64(len(output)) || len(page) != test.expected {
+				t.Fatalf("Wrong page. Size: %v, Page: %v, Expected page: %v", size, len(page), test.expected)
+			}
+			if string(page) != output[test.offset:test.offset+int64(test.expected)] {
+				t.Fatalf("Wrong page content")
+			}
+		})
+	}
+
+	if _, size, err := store.Read(id, int64(len(output))+1, 0); err == nil || size != int64(len(output)) {
+		t.Fatalf("Offset after end is read. Size: %v, Error: %v", size, err)
+	}
+}
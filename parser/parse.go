@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse output by template. Each row maps value name to string or list of strings for List values.
+// Rules of explicit EOF state are applied once to empty line at the end of output instead of implicit Record
+func (o *Template) Parse(output string) ([]map[string]interface{}, error) {
+	p := &parsing{
+		template: o,
+		current:  make([]interface{}, len(o.values)),
+		rows:     []map[string]interface{}{},
+	}
+
+	_, hasEOFState := o.states[StateEOF]
+
+	state := StateStart
+	lines := strings.Split(strings.Replace(output, "\r\n", "\n", -1), "\n")
+
+	for _, line := range lines {
+		var err error
+		if state, err = p.line(state, line); err != nil {
+			return nil, err
+		}
+
+		if state == StateEnd || state == StateEOF {
+			break
+		}
+	}
+
+	if state != StateEnd {
+		if !hasEOFState {
+			p.record()
+		} else if _, err := p.line(StateEOF, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.rows, nil
+}
+
+type parsing struct {
+	template *Template
+	// current values by index of value definition. nil if value is not set
+	current []interface{}
+	rows    []map[string]interface{}
+}
+
+// Apply rules of state to line. Return next state
+func (o *parsing) line(state string, line string) (string, error) {
+	for _, r := range o.template.states[state] {
+		match := r.re.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+
+		o.assign(r, line, match)
+
+		switch r.recordAction {
+		case recordRecord:
+			o.record()
+		case recordClear:
+			o.clear(false)
+		case recordClearall:
+			o.clear(true)
+		}
+
+		if r.newState == stateError {
+			return state, fmt.Errorf("Template.Parse() Error state. Template: %v, Rule: '%v', Line: '%v'", o.template.name, r.line, line)
+		}
+
+		if r.newState != "" {
+			return r.newState, nil
+		}
+
+		if r.lineAction == lineNext {
+			break
+		}
+	}
+
+	return state, nil
+}
+
+func (o *parsing) assign(r rule, line string, match []int) {
+	for group, name := range r.re.SubexpNames() {
+		if name == "" || match[2*group] < 0 {
+			continue
+		}
+
+		text := line[match[2*group]:match[2*group+1]]
+
+		for idx, value := range o.template.values {
+			if value.name != name {
+				continue
+			}
+
+			if value.list {
+				list, _ := o.current[idx].([]string)
+				o.current[idx] = append(list, text)
+			} else {
+				o.current[idx] = text
+			}
+
+			if value.fillup {
+				o.fillup(idx, text)
+			}
+		}
+	}
+}
+
+// Set value in previous rows where it is empty
+func (o *parsing) fillup(idx int, text string) {
+	name := o.template.values[idx].name
+
+	for row := len(o.rows) - 1; row >= 0; row-- {
+		if o.rows[row][name] != "" {
+			break
+		}
+
+		o.rows[row][name] = text
+	}
+}
+
+// Append current values as row. Skipped if all not Filldown values or any Required value is empty
+func (o *parsing) record() {
+	isEmpty := true
+	for idx, value := range o.template.values {
+		if o.current[idx] != nil {
+			isEmpty = isEmpty && value.filldown
+		} else if value.required {
+			o.clear(false)
+
+			return
+		}
+	}
+
+	if isEmpty {
+		return
+	}
+
+	row := make(map[string]interface{}, len(o.template.values))
+	for idx, value := range o.template.values {
+		switch {
+		case o.current[idx] != nil:
+			row[value.name] = o.current[idx]
+		case value.list:
+			row[value.name] = []string{}
+		default:
+			row[value.name] = ""
+		}
+	}
+
+	o.rows = append(o.rows, row)
+	o.clear(false)
+}
+
+// Filldown values are kept unless all is true
+func (o *parsing) clear(all bool) {
+	for idx, value := range o.template.values {
+		if all || !value.filldown {
+			o.current[idx] = nil
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package parser
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, text string, output string) []map[string]interface{} {
+	t.Helper()
+
+	return parseWithName(t, "test", text, output)
+}
+
+func parseWithName(t *testing.T, name string, text string, output string) []map[string]interface{} {
+	t.Helper()
+
+	template, err := Compile(name, text)
+	if err != nil {
+		t.Fatalf("Compile(). Template: %v, Error: %v", name, err)
+	}
+
+	rows, err := template.Parse(output)
+	if err != nil {
+		t.Fatalf("Parse(). Template: %v, Error: %v", name, err)
+	}
+
+	return rows
+}
+
+func expectRows(t *testing.T, expected []map[string]interface{}, actual []map[string]interface{}) {
+	t.Helper()
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Wrong rows.\nExpected: %v\nActual:   %v", expected, actual)
+	}
+}
+
+func TestParseFilldownRequiredList(t *testing.T) {
+	text := `Value Filldown CHASSIS (\S+)
+Value Required SLOT (\d+)
+Value List PORTS (\S+)
+
+Start
+  ^Chassis ${CHASSIS}
+  ^Slot ${SLOT}
+  ^\s+Port ${PORTS}
+  ^End of slot -> Record
+`
+	// the second record has no Required SLOT, the last one has only Filldown CHASSIS
+	output := `Chassis c1
+Slot 1
+  Port a
+  Port b
+End of slot
+End of slot
+Chassis c2
+Slot 2
+  Port c
+End of slot`
+
+	expectRows(t, []map[string]interface{}{
+		{"CHASSIS": "c1", "SLOT": "1", "PORTS": []string{"a", "b"}},
+		{"CHASSIS": "c2", "SLOT": "2", "PORTS": []string{"c"}},
+	}, parse(t, text, output))
+}
+
+func TestParseFillup(t *testing.T) {
+	text := `Value Fillup VLAN (\d+)
+Value PORT (\S+)
+
+Start
+  ^Port ${PORT} -> Record
+  ^Vlan ${VLAN} -> Clearall
+`
+
+	expectRows(t, []map[string]interface{}{
+		{"VLAN": "10", "PORT": "a"},
+		{"VLAN": "10", "PORT": "b"},
+		{"VLAN": "20", "PORT": "c"},
+	}, parse(t, text, "Port a\nPort b\nVlan 10\nPort c\nVlan 20"))
+}
+
+func TestParseContinueRecord(t *testing.T) {
+	text := `Value NAME (\S+)
+Value STATE (up|down)
+
+Start
+  ^\S+ is -> Continue.Record
+  ^${NAME} is ${STATE}
+`
+
+	// the last row is recorded by implicit EOF
+	expectRows(t, []map[string]interface{}{
+		{"NAME": "Gi1", "STATE": "up"},
+		{"NAME": "Gi2", "STATE": "down"},
+	}, parse(t, text, "Gi1 is up\r\nGi2 is down\r\n"))
+}
+
+func TestParseClearall(t *testing.T) {
+	tests := []struct {
+		action   string
+		expected []map[string]interface{}
+	}{
+		{
+			action:   "Clear",
+			expected: []map[string]interface{}{{"DEVICE": "d1", "PORT": "a"}, {"DEVICE": "d1", "PORT": "b"}},
+		},
+		{
+			action:   "Clearall",
+			expected: []map[string]interface{}{{"DEVICE": "d1", "PORT": "a"}, {"DEVICE": "", "PORT": "b"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.action, func(t *testing.T) {
+			text := `Value Filldown DEVICE (\S+)
+Value PORT (\S+)
+
+Start
+  ^Device ${DEVICE}
+  ^Port ${PORT} -> Record
+  ^Reset -> ` + test.action + `
+`
+
+			expectRows(t, test.expected, parse(t, text, "Device d1\nPort a\nReset\nPort b"))
+		})
+	}
+}
+
+func TestParseErrorState(t *testing.T) {
+	text := `Value NAME (\S+)
+
+Start
+  ^Name ${NAME} -> Record
+  ^% -> Error
+`
+
+	template, err := Compile("test", text)
+	if err != nil {
+		t.Fatalf("Compile(). Error: %v", err)
+	}
+
+	rows, err := template.Parse("Name a\n% Invalid input detected\nName b")
+	if err == nil || !strings.Contains(err.Error(), "Error state") {
+		t.Fatalf("Error state is not reported. Rows: %v, Error: %v", rows, err)
+	}
+	if rows != nil {
+		t.Fatalf("Rows are returned with error: %v", rows)
+	}
+}
+
+func TestParseEOF(t *testing.T) {
+	values := `Value NAME (\S+)
+Value TOTAL (\d+)
+
+Start
+  ^Name ${NAME} -> Record
+  ^Total ${TOTAL}
+`
+	output := "Name a\nTotal 3"
+	// lines after Stop are not parsed
+	stopOutput := "Name a\nTotal 3\nStop\nName c"
+
+	tests := []struct {
+		name     string
+		states   string
+		output   string
+		expected []map[string]interface{}
+	}{
+		{
+			name:     "implicit",
+			expected: []map[string]interface{}{{"NAME": "a", "TOTAL": ""}, {"NAME": "", "TOTAL": "3"}},
+		},
+		{
+			name:     "empty state disables record",
+			states:   "\nEOF\n",
+			expected: []map[string]interface{}{{"NAME": "a", "TOTAL": ""}},
+		},
+		{
+			name:     "rules of state are run",
+			states:   "\nEOF\n  ^$$ -> Record\n",
+			expected: []map[string]interface{}{{"NAME": "a", "TOTAL": ""}, {"NAME": "", "TOTAL": "3"}},
+		},
+		{
+			name:     "rules of state are run after transition",
+			states:   "  ^Stop -> EOF\n\nEOF\n  ^$$ -> Record\n",
+			output:   stopOutput,
+			expected: []map[string]interface{}{{"NAME": "a", "TOTAL": ""}, {"NAME": "", "TOTAL": "3"}},
+		},
+		{
+			name:     "end state",
+			states:   "  ^Stop -> End\n\nEOF\n  ^$$ -> Record\n",
+			output:   stopOutput,
+			expected: []map[string]interface{}{{"NAME": "a", "TOTAL": ""}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.output == "" {
+				test.output = output
+			}
+
+			expectRows(t, test.expected, parse(t, values+test.states, test.output))
+		})
+	}
+}
+
+func TestParseCiscoIosShowInterfaces(t *testing.T) {
+	text, err := os.ReadFile("../templates/cisco_ios_show_interfaces.textfsm")
+	if err != nil {
+		t.Fatalf("Read template. Error: %v", err)
+	}
+
+	output := `GigabitEthernet0/0 is up, line protocol is up
+  Hardware is iGbE, address is 5254.0012.3456 (bia 5254.0012.3456)
+  Description: uplink to core
+  Internet address is 10.0.0.1/24
+  MTU 1500 bytes, BW 1000000 Kbit/sec, DLY 10 usec,
+     reliability 255/255, txload 1/255, rxload 1/255
+GigabitEthernet0/1 is administratively down, line protocol is down
+  Hardware is iGbE, address is 5254.0012.3457 (bia 5254.0012.3457)
+  MTU 1500 bytes, BW 1000000 Kbit/sec, DLY 10 usec,
+Loopback0 is up, line protocol is up
+  Hardware is Loopback
+  Internet address is 192.168.255.1/32
+  MTU 1514 bytes, BW 8000000 Kbit/sec, DLY 5000 usec, `
+
+	expectRows(t, []map[string]interface{}{
+		{
+			"INTERFACE": "GigabitEthernet0/0", "LINK_STATUS": "up", "PROTOCOL_STATUS": "up",
+			"HARDWARE_TYPE": "iGbE", "ADDRESS": "5254.0012.3456", "DESCRIPTION": "uplink to core",
+			"IP_ADDRESS": "10.0.0.1/24", "MTU": "1500", "BANDWIDTH": "1000000 Kbit",
+		},
+		{
+			"INTERFACE": "GigabitEthernet0/1", "LINK_STATUS": "administratively down", "PROTOCOL_STATUS": "down",
+			"HARDWARE_TYPE": "iGbE", "ADDRESS": "5254.0012.3457", "DESCRIPTION": "",
+			"IP_ADDRESS": "", "MTU": "1500", "BANDWIDTH": "1000000 Kbit",
+		},
+		{
+			"INTERFACE": "Loopback0", "LINK_STATUS": "up", "PROTOCOL_STATUS": "up",
+			"HARDWARE_TYPE": "Loopback", "ADDRESS": "", "DESCRIPTION": "",
+			"IP_ADDRESS": "192.168.255.1/32", "MTU": "1514", "BANDWIDTH": "8000000 Kbit",
+		},
+	}, parseWithName(t, "cisco_ios_show_interfaces", string(text), output))
+}
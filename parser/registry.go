@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+const (
+	TemplateFileExt = ".textfsm"
+)
+
+// e.g. cisco_ios_show_interfaces
+var templateNameRegexp = regexp.MustCompile(`^[0-9A-Za-z_.-]+$`)
+
+func NewRegistry() *Registry {
+	return &Registry{
+		templates: map[string]*Template{},
+	}
+}
+
+// Named templates. Name usually consists of profile and command
+type Registry struct {
+	templates map[string]*Template
+	mutex     sync.RWMutex
+}
+
+// Load *.textfsm files of dir. Template name is file name without extension
+func (o *Registry) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+TemplateFileExt))
+	if err != nil {
+		return fmt.Errorf("Registry.LoadDir() Dir: %v, Error: %v", dir, err)
+	}
+
+	for _, path := range files {
+		text, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Registry.LoadDir() Read file. Path: %v, Error: %v", path, err)
+		}
+
+		if err := o.Put(strings.TrimSuffix(filepath.Base(path), TemplateFileExt), string(text)); err != nil {
+			return err
+		}
+	}
+
+	glog.Infof("Registry.LoadDir() Dir: %v, Templates: %v", dir, len(files))
+
+	return nil
+}
+
+// Compile and add or replace template
+func (o *Registry) Put(name string, text string) error {
+	if !templateNameRegexp.MatchString(name) {
+		return fmt.Errorf("Registry.Put() Wrong template name: '%v'", name)
+	}
+
+	template, err := Compile(name, text)
+	if err != nil {
+		return err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.templates[name] = template
+	glog.Infof("Registry.Put() Name: %v", name)
+
+	return nil
+}
+
+func (o *Registry) Get(name string) (*Template, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	template, ok := o.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("Registry.Get() Template not found. Name: %v", name)
+	}
+
+	return template, nil
+}
+
+func (o *Registry) Delete(name string) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.templates[name]; !ok {
+		return fmt.Errorf("Registry.Delete() Template not found. Name: %v", name)
+	}
+
+	delete(o.templates, name)
+
+	return nil
+}
+
+// Sorted template names
+func (o *Registry) List() []string {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	res := make([]string, 0, len(o.templates))
+	for name := range o.templates {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+
+	return res
+}
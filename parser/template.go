@@ -0,0 +1,359 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	StateStart = "Start"
+	StateEnd   = "End"
+	// explicit EOF state disables implicit Record at the end of output
+	StateEOF = "EOF"
+
+	stateError = "Error"
+
+	lineNext     = "Next"
+	lineContinue = "Continue"
+
+	recordNo       = "NoRecord"
+	recordRecord   = "Record"
+	recordClear    = "Clear"
+	recordClearall = "Clearall"
+
+	optionFilldown = "Filldown"
+	optionFillup   = "Fillup"
+	optionRequired = "Required"
+	optionList     = "List"
+	optionKey      = "Key"
+)
+
+var (
+	stateNameRegexp    = regexp.MustCompile(`^\w+$`)
+	valueNameRegexp    = regexp.MustCompile(`^\w+$`)
+	substitutionRegexp = regexp.MustCompile(`\$\$|\$\{(\w+)\}|\$(\w+)`)
+)
+
+type valueDef struct {
+	name     string
+	regex    string
+	filldown bool
+	fillup   bool
+	required bool
+	list     bool
+}
+
+type rule struct {
+	line         string
+	re           *regexp.Regexp
+	lineAction   string
+	recordAction string
+	newState     string
+}
+
+// Template in TextFSM format: Value definitions, blank line and states with rules.
+// Supported value options: Filldown, Fillup, Required, List, Key
+type Template struct {
+	name   string
+	text   string
+	values []valueDef
+	states map[string][]rule
+}
+
+func (o *Template) GetName() string {
+	return o.name
+}
+
+func (o *Template) GetText() string {
+	return o.text
+}
+
+func Compile(name string, text string) (*Template, error) {
+	o := &Template{
+		name:   name,
+		text:   text,
+		states: map[string][]rule{},
+	}
+
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+
+	idx, err := o.compileValues(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.compileStates(lines[idx:]); err != nil {
+		return nil, err
+	}
+
+	if _, ok := o.states[StateStart]; !ok {
+		return nil, fmt.Errorf("Compile() State %v is not defined. Template: %v", StateStart, name)
+	}
+
+	for state, rules := range o.states {
+		for _, r := range rules {
+			if r.newState == "" || r.newState == StateEnd || r.newState == StateEOF || r.newState == stateError {
+				continue
+			}
+
+			if _, ok := o.states[r.newState]; !ok {
+				return nil, fmt.Errorf("Compile() Unknown state. Template: %v, State: %v, Rule: '%v'", name, state, r.line)
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Return index of the first line after values
+func (o *Template) compileValues(lines []string) (int, error) {
+	names := map[string]bool{}
+
+	for idx, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			if len(o.values) == 0 {
+				continue
+			}
+
+			return idx + 1, nil
+		}
+
+		if !strings.HasPrefix(line, "Value ") {
+			return 0, fmt.Errorf("compileValues() Expected Value definition. Template: %v, Line: '%v'", o.name, line)
+		}
+
+		value, err := parseValue(strings.TrimSpace(strings.TrimPrefix(line, "Value ")))
+		if err != nil {
+			return 0, fmt.Errorf("compileValues() Template: %v, Line: '%v', Error: %v", o.name, line, err)
+		}
+
+		if names[value.name] {
+			return 0, fmt.Errorf("compileValues() Duplicate value. Template: %v, Value: %v", o.name, value.name)
+		}
+		names[value.name] = true
+
+		o.values = append(o.values, value)
+	}
+
+	return 0, fmt.Errorf("compileValues() States are not defined. Template: %v", o.name)
+}
+
+func parseValue(definition string) (valueDef, error) {
+	value := valueDef{}
+
+	fields := strings.Fields(definition)
+	if len(fields) < 2 {
+		return value, fmt.Errorf("wrong value definition")
+	}
+
+	// options are optional: "[Options] Name (regex)"
+	rest := definition
+	if len(fields) > 2 && !strings.HasPrefix(fields[1], "(") {
+		for _, option := range strings.Split(fields[0], ",") {
+			switch option {
+			case optionFilldown:
+				value.filldown = true
+			case optionFillup:
+				value.fillup = true
+			case optionRequired:
+				value.required = true
+			case optionList:
+				value.list = true
+			case optionKey:
+			default:
+				return value, fmt.Errorf("unknown option %v", option)
+			}
+		}
+
+		rest = strings.TrimSpace(rest[len(fields[0]):])
+		fields = fields[1:]
+	}
+
+	value.name = fields[0]
+	value.regex = strings.TrimSpace(rest[len(fields[0]):])
+
+	if !valueNameRegexp.MatchString(value.name) {
+		return value, fmt.Errorf("wrong value name %v", value.name)
+	}
+
+	if !strings.HasPrefix(value.regex, "(") || !strings.HasSuffix(value.regex, ")") {
+		return value, fmt.Errorf("value regex should be in parentheses")
+	}
+
+	if _, err := regexp.Compile(value.regex); err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+func (o *Template) compileStates(lines []string) error {
+	state := ""
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" {
+			state = ""
+
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if line[0] != ' ' && line[0] != '\t' {
+			if !stateNameRegexp.MatchString(trimmed) {
+				return fmt.Errorf("compileStates() Wrong state name. Template: %v, Line: '%v'", o.name, line)
+			}
+
+			if _, ok := o.states[trimmed]; ok {
+				return fmt.Errorf("compileStates() Duplicate state. Template: %v, State: %v", o.name, trimmed)
+			}
+
+			state = trimmed
+			o.states[state] = []rule{}
+
+			continue
+		}
+
+		if state == "" {
+			return fmt.Errorf("compileStates() Rule without state. Template: %v, Line: '%v'", o.name, line)
+		}
+
+		r, err := o.parseRule(trimmed)
+		if err != nil {
+			return fmt.Errorf("compileStates() Template: %v, State: %v, Line: '%v', Error: %v", o.name, state, line, err)
+		}
+
+		o.states[state] = append(o.states[state], r)
+	}
+
+	return nil
+}
+
+// "^regex -> LineAction.RecordAction NewState"
+func (o *Template) parseRule(line string) (rule, error) {
+	r := rule{
+		line:         line,
+		lineAction:   lineNext,
+		recordAction: recordNo,
+	}
+
+	if !strings.HasPrefix(line, "^") {
+		return r, fmt.Errorf("rule should start with ^")
+	}
+
+	pattern := line
+	if idx := strings.LastIndex(line, " -> "); idx >= 0 {
+		pattern = strings.TrimSpace(line[:idx])
+
+		if err := r.parseAction(strings.TrimSpace(line[idx+4:])); err != nil {
+			return r, err
+		}
+	}
+
+	expanded, err := o.expand(pattern)
+	if err != nil {
+		return r, err
+	}
+
+	r.re, err = regexp.Compile(expanded)
+	if err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
+
+func (o *rule) parseAction(action string) error {
+	fields := strings.Fields(action)
+	if len(fields) == 0 {
+		return fmt.Errorf("empty action")
+	}
+
+	if fields[0] == stateError {
+		o.newState = stateError
+
+		return nil
+	}
+
+	if len(fields) > 2 {
+		return fmt.Errorf("wrong action '%v'", action)
+	}
+
+	isAction := true
+	parts := strings.SplitN(fields[0], ".", 2)
+	switch {
+	case len(parts) == 2:
+		o.lineAction = parts[0]
+		o.recordAction = parts[1]
+	case isLineAction(parts[0]):
+		o.lineAction = parts[0]
+	case isRecordAction(parts[0]):
+		o.recordAction = parts[0]
+	default:
+		isAction = false
+	}
+
+	if !isLineAction(o.lineAction) || !isRecordAction(o.recordAction) {
+		return fmt.Errorf("wrong action '%v'", action)
+	}
+
+	if !isAction {
+		if len(fields) > 1 {
+			return fmt.Errorf("wrong action '%v'", action)
+		}
+		o.newState = fields[0]
+	} else if len(fields) == 2 {
+		o.newState = fields[1]
+	}
+
+	if o.newState != "" && o.lineAction == lineContinue {
+		return fmt.Errorf("state change is not allowed with Continue")
+	}
+
+	if o.newState != "" && !stateNameRegexp.MatchString(o.newState) {
+		return fmt.Errorf("wrong state name '%v'", o.newState)
+	}
+
+	return nil
+}
+
+// Replace ${Name} and $Name by named group with value regex, $$ by $
+func (o *Template) expand(pattern string) (string, error) {
+	var err error
+
+	expanded := substitutionRegexp.ReplaceAllStringFunc(pattern, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+
+		name := strings.Trim(match, "${}")
+		for _, value := range o.values {
+			if value.name == name {
+				return "(?P<" + name + ">" + value.regex[1:]
+			}
+		}
+
+		err = fmt.Errorf("unknown value %v", name)
+
+		return match
+	})
+
+	return expanded, err
+}
+
+func isLineAction(action string) bool {
+	return action == lineNext || action == lineContinue
+}
+
+func isRecordAction(action string) bool {
+	return action == recordNo || action == recordRecord || action == recordClear || action == recordClearall
+}
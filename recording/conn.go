@@ -0,0 +1,34 @@
+package recording
+
+import "net"
+
+// Record data read from and written to connection
+func NewConn(conn net.Conn, recorder *Recorder) net.Conn {
+	return &recordingConn{
+		Conn:     conn,
+		recorder: recorder,
+	}
+}
+
+type recordingConn struct {
+	net.Conn
+	recorder *Recorder
+}
+
+func (o *recordingConn) Read(buf []byte) (int, error) {
+	n, err := o.Conn.Read(buf)
+	if n > 0 {
+		o.recorder.Output(buf[:n])
+	}
+
+	return n, err
+}
+
+func (o *recordingConn) Write(buf []byte) (int, error) {
+	n, err := o.Conn.Write(buf)
+	if n > 0 {
+		o.recorder.Input(buf[:n])
+	}
+
+	return n, err
+}
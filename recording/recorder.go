@@ -0,0 +1,157 @@
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	AsciicastVersion = 2
+	DefaultWidth     = 80
+	DefaultHeight    = 24
+
+	eventOutput = "o"
+	eventInput  = "i"
+
+	MaskedInput = "********"
+)
+
+type asciicastHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title,omitempty"`
+}
+
+// Write session transcript in asciicast v2 format.
+// Events are dropped when file reaches size limit
+type Recorder struct {
+	file     *os.File
+	start    time.Time
+	maxBytes int64
+	size     int64
+	// replace next input by MaskedInput
+	mask      bool
+	isClose   bool
+	isLimited bool
+	mutex     sync.Mutex
+}
+
+func newRecorder(path string, title string, width int, height int, maxBytes int64) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("newRecorder() Create file. Path: %v, Error: %v", path, err)
+	}
+
+	o := &Recorder{
+		file:     file,
+		start:    time.Now(),
+		maxBytes: maxBytes,
+	}
+
+	header := asciicastHeader{
+		Version:   AsciicastVersion,
+		Width:     width,
+		Height:    height,
+		Timestamp: o.start.Unix(),
+		Title:     title,
+	}
+
+	if err := o.writeLine(header); err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Data received from device or command
+func (o *Recorder) Output(data []byte) {
+	o.event(eventOutput, string(data))
+}
+
+// Data sent to device or command
+func (o *Recorder) Input(data []byte) {
+	o.mutex.Lock()
+	mask := o.mask
+	o.mask = false
+	o.mutex.Unlock()
+
+	if mask {
+		o.event(eventInput, MaskedInput)
+
+		return
+	}
+
+	o.event(eventInput, string(data))
+}
+
+// Mask next input. Used for passwords
+func (o *Recorder) MaskNextInput() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.mask = true
+}
+
+func (o *Recorder) Close() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.isClose {
+		return
+	}
+	o.isClose = true
+
+	if err := o.file.Close(); err != nil {
+		glog.Errorf("Recorder.Close() Path: %v, Error: %v", o.file.Name(), err)
+	}
+}
+
+func (o *Recorder) event(kind string, data string) {
+	if data == "" {
+		return
+	}
+
+	elapsed := time.Since(o.start).Seconds()
+	if err := o.writeLine([]interface{}{elapsed, kind, data}); err != nil {
+		glog.Errorf("Recorder.event() Error: %v", err)
+	}
+}
+
+func (o *Recorder) writeLine(value interface{}) error {
+	line, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("Recorder.writeLine() Marshal. Error: %v", err)
+	}
+	line = append(line, '\n')
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.isClose || o.isLimited {
+		return nil
+	}
+
+	if o.maxBytes > 0 && o.size+int64(len(line)) > o.maxBytes {
+		glog.Infof("Recorder.writeLine() Size limit is reached. Stop recording. Path: %v, Limit: %v", o.file.Name(), o.maxBytes)
+		o.isLimited = true
+
+		return nil
+	}
+
+	n, err := o.file.Write(line)
+	o.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("Recorder.writeLine() Write. Path: %v, Error: %v", o.file.Name(), err)
+	}
+
+	return nil
+}
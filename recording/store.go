@@ -0,0 +1,111 @@
+package recording
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	FileExt = ".cast"
+
+	cleanupInterval = time.Hour
+)
+
+var sessionIdRegexp = regexp.MustCompile(`^[0-9A-Za-z_-]+$`)
+
+func NewStore(dir string, maxBytes int64, retention time.Duration, recordAll bool) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("NewStore() Create dir. Dir: %v, Error: %v", dir, err)
+	}
+
+	return &Store{
+		dir:       dir,
+		maxBytes:  maxBytes,
+		retention: retention,
+		recordAll: recordAll,
+	}, nil
+}
+
+// Keep recordings of sessions as <dir>/<session id>.cast
+type Store struct {
+	dir       string
+	maxBytes  int64
+	retention time.Duration
+	recordAll bool
+}
+
+// True if session should be recorded
+func (o *Store) ShouldRecord(requested bool) bool {
+	return o != nil && (o.recordAll || requested)
+}
+
+func (o *Store) Create(sessID string, title string, width int, height int) (*Recorder, error) {
+	path, err := o.Path(sessID)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder, err := newRecorder(path, title, width, height, o.maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	glog.Infof("Store.Create() Record session. ID: %v, Path: %v", sessID, path)
+
+	return recorder, nil
+}
+
+func (o *Store) Path(sessID string) (string, error) {
+	if !sessionIdRegexp.MatchString(sessID) {
+		return "", fmt.Errorf("Store.Path() Wrong session id: '%v'", sessID)
+	}
+
+	return filepath.Join(o.dir, sessID+FileExt), nil
+}
+
+// Remove recordings older than retention periodically
+func (o *Store) StartCleanup() {
+	if o.retention <= 0 {
+		return
+	}
+
+	go func() {
+		for {
+			o.cleanup()
+			time.Sleep(cleanupInterval)
+		}
+	}()
+}
+
+func (o *Store) cleanup() {
+	logPrefix := "Store.cleanup()"
+
+	files, err := ioutil.ReadDir(o.dir)
+	if err != nil {
+		glog.Errorf("%v Read dir. Dir: %v, Error: %v", logPrefix, o.dir, err)
+
+		return
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), FileExt) || time.Since(file.ModTime()) < o.retention {
+			continue
+		}
+
+		path := filepath.Join(o.dir, file.Name())
+		if err := os.Remove(path); err != nil {
+			glog.Errorf("%v Remove. Path: %v, Error: %v", logPrefix, path, err)
+
+			continue
+		}
+
+		glog.Infof("%v Removed. Path: %v", logPrefix, path)
+	}
+}
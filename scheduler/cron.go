@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type cronField struct {
+	min int
+	max int
+}
+
+var (
+	minuteField  = cronField{0, 59}
+	hourField    = cronField{0, 23}
+	dayField     = cronField{1, 31}
+	monthField   = cronField{1, 12}
+	weekdayField = cronField{0, 7}
+
+	cronMacros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// Parsed cron expression. Precision is one minute
+type Schedule struct {
+	minutes  map[int]bool
+	hours    map[int]bool
+	days     map[int]bool
+	months   map[int]bool
+	weekdays map[int]bool
+	// day matches if day-of-month or day-of-week matches when both are restricted
+	daysAny     bool
+	weekdaysAny bool
+}
+
+// Parse "minute hour day-of-month month day-of-week" with *, lists, ranges and steps, or @hourly like macro
+func ParseCron(expr string) (*Schedule, error) {
+	if macro, ok := cronMacros[strings.TrimSpace(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("ParseCron() Expected 5 fields. Cron: '%v'", expr)
+	}
+
+	o := &Schedule{
+		daysAny:     fields[2] == "*",
+		weekdaysAny: fields[4] == "*",
+	}
+
+	var err error
+	if o.minutes, err = parseCronField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if o.hours, err = parseCronField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if o.days, err = parseCronField(fields[2], dayField); err != nil {
+		return nil, err
+	}
+	if o.months, err = parseCronField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if o.weekdays, err = parseCronField(fields[4], weekdayField); err != nil {
+		return nil, err
+	}
+
+	// 7 is Sunday too
+	if o.weekdays[7] {
+		o.weekdays[0] = true
+	}
+
+	return o, nil
+}
+
+func (o *Schedule) Matches(t time.Time) bool {
+	if !o.minutes[t.Minute()] || !o.hours[t.Hour()] || !o.months[int(t.Month())] {
+		return false
+	}
+
+	day := o.days[t.Day()]
+	weekday := o.weekdays[int(t.Weekday())]
+
+	switch {
+	case o.daysAny && o.weekdaysAny:
+		return true
+	case o.daysAny:
+		return weekday
+	case o.weekdaysAny:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+func parseCronField(value string, field cronField) (map[int]bool, error) {
+	res := map[int]bool{}
+
+	for _, item := range strings.Split(value, ",") {
+		rangeValue := item
+		step := 1
+
+		if idx := strings.Index(item, "/"); idx >= 0 {
+			var err error
+			step, err = strconv.Atoi(item[idx+1:])
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("parseCronField() Wrong step. Item: '%v'", item)
+			}
+			rangeValue = item[:idx]
+		}
+
+		from, to := field.min, field.max
+		if rangeValue != "*" {
+			var err error
+			bounds := strings.SplitN(rangeValue, "-", 2)
+
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("parseCronField() Wrong value. Item: '%v'", item)
+			}
+
+			to = from
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("parseCronField() Wrong value. Item: '%v'", item)
+				}
+			} else if step > 1 {
+				// "5/10" means from 5 to max
+				to = field.max
+			}
+		}
+
+		if from < field.min || to > field.max || from > to {
+			return nil, fmt.Errorf("parseCronField() Value out of range %v-%v. Item: '%v'", field.min, field.max, item)
+		}
+
+		for i := from; i <= to; i += step {
+			res[i] = true
+		}
+	}
+
+	return res, nil
+}
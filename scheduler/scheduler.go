@@ -0,0 +1,295 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/deminds/CmdProxy/charset"
+	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/inventory"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/session"
+	"github.com/deminds/CmdProxy/session/types"
+	"github.com/deminds/CmdProxy/webhook"
+	"github.com/golang/glog"
+)
+
+func NewScheduler(
+	idGenerator *generatorid.IDGenerator,
+	deviceInventory *inventory.Inventory,
+	consoleFactory *types.ConsoleSessionFactory,
+	telnetFactory *types.TelnetSessionFactory,
+	keepResults int,
+	dispatcher *webhook.Dispatcher) *Scheduler {
+
+	if keepResults < 1 {
+		keepResults = 1
+	}
+
+	return &Scheduler{
+		idGenerator:    idGenerator,
+		inventory:      deviceInventory,
+		consoleFactory: consoleFactory,
+		telnetFactory:  telnetFactory,
+		keepResults:    keepResults,
+		jobs:           map[string]*job{},
+		dispatcher:     dispatcher,
+	}
+}
+
+// Run commands of registered jobs by cron schedule
+type Scheduler struct {
+	idGenerator *generatorid.IDGenerator
+	inventory   *inventory.Inventory
+	// console jobs get service limits, recording and events as connected sessions
+	consoleFactory *types.ConsoleSessionFactory
+	telnetFactory  *types.TelnetSessionFactory
+	// default number of kept runs
+	keepResults int
+
+	jobs  map[string]*job
+	mutex sync.RWMutex
+
+	dispatcher *webhook.Dispatcher
+}
+
+type job struct {
+	model.ScheduledJob
+	schedule *Schedule
+	runs     []model.JobRun
+	// previous run is not finished
+	isRunning bool
+}
+
+func (o *Scheduler) Add(scheduledJob model.ScheduledJob) (model.ScheduledJob, error) {
+	schedule, err := ParseCron(scheduledJob.Cron)
+	if err != nil {
+		return scheduledJob, err
+	}
+
+	if scheduledJob.Target == model.JobTargetDevice {
+		if _, err := o.inventory.Get(scheduledJob.Device); err != nil {
+			return scheduledJob, err
+		}
+	}
+
+	id, err := o.idGenerator.Next()
+	if err != nil {
+		return scheduledJob, fmt.Errorf("Scheduler.Add() Generate id. Error: %v", err)
+	}
+	scheduledJob.Id = id
+
+	if scheduledJob.KeepResults == 0 {
+		scheduledJob.KeepResults = o.keepResults
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.jobs[id] = &job{
+		ScheduledJob: scheduledJob,
+		schedule:     schedule,
+	}
+
+	glog.Infof("Scheduler.Add() Job: %+v", scheduledJob)
+
+	return scheduledJob, nil
+}
+
+func (o *Scheduler) Get(id string) (model.JobResponse, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	j, ok := o.jobs[id]
+	if !ok {
+		return model.JobResponse{}, fmt.Errorf("Scheduler.Get() Job not found. ID: %v", id)
+	}
+
+	runs := make([]model.JobRun, len(j.runs))
+	copy(runs, j.runs)
+
+	return model.JobResponse{
+		ScheduledJob: j.ScheduledJob,
+		Runs:         runs,
+	}, nil
+}
+
+// Jobs sorted by id
+func (o *Scheduler) List() []model.ScheduledJob {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	res := make([]model.ScheduledJob, 0, len(o.jobs))
+	for _, j := range o.jobs {
+		res = append(res, j.ScheduledJob)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+
+	return res
+}
+
+// Running job is finished, but its result is dropped
+func (o *Scheduler) Delete(id string) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.jobs[id]; !ok {
+		return fmt.Errorf("Scheduler.Delete() Job not found. ID: %v", id)
+	}
+
+	delete(o.jobs, id)
+	glog.Infof("Scheduler.Delete() ID: %v", id)
+
+	return nil
+}
+
+// Check schedules at the beginning of every minute
+func (o *Scheduler) Start() {
+	glog.Infof("Scheduler.Start()")
+
+	go func() {
+		for {
+			now := time.Now()
+			next := now.Truncate(time.Minute).Add(time.Minute)
+			time.Sleep(next.Sub(now))
+
+			o.runDue(next)
+		}
+	}()
+}
+
+func (o *Scheduler) runDue(t time.Time) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for _, j := range o.jobs {
+		if !j.schedule.Matches(t) {
+			continue
+		}
+
+		if j.isRunning {
+			glog.Infof("Scheduler.runDue() Previous run is not finished. Skip. ID: %v", j.Id)
+
+			continue
+		}
+
+		j.isRunning = true
+		go o.run(j.ScheduledJob)
+	}
+}
+
+func (o *Scheduler) run(scheduledJob model.ScheduledJob) {
+	logPrefix := "Scheduler.run()"
+	glog.Infof("%v ID: %v, Name: %v", logPrefix, scheduledJob.Id, scheduledJob.Name)
+
+	run := model.JobRun{
+		JobId:   scheduledJob.Id,
+		Started: time.Now(),
+		Status:  model.Ok,
+	}
+
+	results, err := o.execute(scheduledJob)
+	run.Results = results
+	run.Finished = time.Now()
+	if err != nil {
+		glog.Errorf("%v ID: %v, Error: %v", logPrefix, scheduledJob.Id, err)
+		run.Status = model.Error
+		run.Error = err.Error()
+	}
+
+	o.saveRun(run)
+
+	event := model.WebhookEvent{
+		Type:  model.EventJobFinished,
+		Error: run.Error,
+		Job:   &run,
+	}
+
+	o.dispatcher.Notify(event)
+	if scheduledJob.Webhook != "" {
+		o.dispatcher.Deliver(model.Webhook{Url: scheduledJob.Webhook}, event)
+	}
+}
+
+func (o *Scheduler) saveRun(run model.JobRun) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	j, ok := o.jobs[run.JobId]
+	if !ok {
+		glog.Infof("Scheduler.saveRun() Job was deleted. ID: %v", run.JobId)
+
+		return
+	}
+
+	j.isRunning = false
+	j.runs = append(j.runs, run)
+	if len(j.runs) > j.KeepResults {
+		j.runs = j.runs[len(j.runs)-j.KeepResults:]
+	}
+}
+
+// Execute commands in one session. Stop on first error
+func (o *Scheduler) execute(scheduledJob model.ScheduledJob) ([]model.CommandResult, error) {
+	results := []model.CommandResult{}
+
+	sess, err := o.newSession(scheduledJob)
+	if err != nil {
+		return results, err
+	}
+
+	if err := sess.Connect(); err != nil {
+		return results, err
+	}
+
+	defer func() {
+		if !sess.IsClose() {
+			sess.Close()
+		}
+	}()
+
+	for _, command := range scheduledJob.Commands {
+		res := model.CommandResult{
+			Command: command,
+			Status:  model.Ok,
+		}
+
+		cmdResult, err := sess.Command(command, session.CommandOptions{Raw: scheduledJob.Raw})
+		if err != nil {
+			res.Status = model.Error
+			res.Error = err.Error()
+			results = append(results, res)
+
+			return results, err
+		}
+
+		res.Output, res.Replacements = charset.ToUtf8(cmdResult.Output, cmdResult.Charset)
+		res.Prompt, _ = charset.ToUtf8(cmdResult.Prompt, cmdResult.Charset)
+		res.Mode = string(cmdResult.Mode)
+		res.ReadLimitReached = cmdResult.Truncated
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+func (o *Scheduler) newSession(scheduledJob model.ScheduledJob) (session.ISession, error) {
+	if scheduledJob.Target == model.JobTargetConsole {
+		return o.consoleFactory.New(model.ConnectConsoleRequest{})
+	}
+
+	requestData, err := o.inventory.Resolve(model.ConnectTelnetRequest{Device: scheduledJob.Device})
+	if err != nil {
+		return nil, err
+	}
+	if !requestData.IsValid() {
+		return nil, fmt.Errorf("device settings are not valid. Device: %v", scheduledJob.Device)
+	}
+
+	return o.telnetFactory.New(requestData)
+}
@@ -0,0 +1,46 @@
+package session
+
+import (
+	"regexp"
+	"strings"
+)
+
+type CliMode string
+
+const (
+	CliModeUnknown    CliMode = ""
+	CliModeUser       CliMode = "user"
+	CliModePrivileged CliMode = "privileged"
+	CliModeConfig     CliMode = "config"
+)
+
+var (
+	// router(config)# router(config-if)# router(config-router)>
+	ciscoSubModeRegexp = regexp.MustCompile(`\((config[\w-]*)\)\s*[#>]$`)
+	// [HUAWEI] [~HUAWEI-GigabitEthernet0/0/1]
+	huaweiSystemViewRegexp = regexp.MustCompile(`^\[[~*]?[^\]]+\]$`)
+	// <HUAWEI>
+	huaweiUserViewRegexp = regexp.MustCompile(`^<[^>]+>$`)
+)
+
+// Infer CLI mode from device prompt. Return CliModeUnknown if prompt is not recognized
+func DetectCliMode(prompt string) CliMode {
+	prompt = strings.TrimSpace(prompt)
+
+	if m := ciscoSubModeRegexp.FindStringSubmatch(prompt); m != nil {
+		return CliMode(m[1])
+	}
+
+	switch {
+	case huaweiSystemViewRegexp.MatchString(prompt):
+		return CliModeConfig
+	case huaweiUserViewRegexp.MatchString(prompt):
+		return CliModeUser
+	case strings.HasSuffix(prompt, "#"):
+		return CliModePrivileged
+	case strings.HasSuffix(prompt, ">"), strings.HasSuffix(prompt, "$"):
+		return CliModeUser
+	}
+
+	return CliModeUnknown
+}
@@ -0,0 +1,31 @@
+package session
+
+import (
+	"regexp"
+	"time"
+)
+
+type CommandOptions struct {
+	// Return output as is. By default telnet output is cleaned from
+	// echoed command, trailing prompt, pager artifacts and control sequences
+	Raw bool
+	// Questions asked by device after command and answers for them
+	Expect []ExpectStep
+	// Command is aborted when closed, e.g. request context is done.
+	// Process of console command is killed. nil - never
+	Cancel <-chan struct{}
+	// Input of console command. Command has no input if nil and KeepStdinOpen is false
+	Stdin []byte
+	// Input of console command is kept open after Stdin for IStdinSession.WriteStdin
+	KeepStdinOpen bool
+	// Called with output while command runs, before output is processed. Telnet output is passed
+	// by lines without prompt. Chunk is valid only during call. nil - output is returned at the end only
+	OutputChunk func(chunk []byte)
+}
+
+type ExpectStep struct {
+	Expect *regexp.Regexp
+	Send   string
+	// Session timeout is used if empty
+	Timeout time.Duration
+}
@@ -0,0 +1,16 @@
+package session
+
+import "github.com/deminds/CmdProxy/model"
+
+type CommandResult struct {
+	Output string
+	// Prompt matched after command output. Empty for sessions without prompt
+	Prompt string
+	Mode   CliMode
+	// Charset of Output and Prompt. utf-8 if empty
+	Charset string
+	// How command process ended. nil for sessions without process
+	Exit *model.ProcessExit
+	// Output is cut by read limit of session, the rest of output is dropped
+	Truncated bool
+}
@@ -2,10 +2,17 @@ package session
 
 type ISession interface {
 	Connect() error
-	Command(command string) (string, error)
+	Command(command string, options CommandOptions) (CommandResult, error)
 	Ping() bool
 	GetId() string
 	GetType() SessionType
 	IsClose() bool
 	Close()
 }
+
+// Session writing input to running command
+type IStdinSession interface {
+	// Write to input of running command started with KeepStdinOpen. Input is closed if close is true.
+	// Secret input, e.g. password, is masked in recording
+	WriteStdin(data []byte, close bool, secret bool) error
+}
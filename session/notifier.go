@@ -0,0 +1,8 @@
+package session
+
+import "github.com/deminds/CmdProxy/model"
+
+// Receive session events
+type INotifier interface {
+	Notify(event model.WebhookEvent)
+}
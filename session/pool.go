@@ -18,7 +18,10 @@ type SessionPool struct {
 }
 
 func (o *SessionPool) Get(sessID string) (ISession, error) {
+	o.mutex.Lock()
 	sess, exist := o.sessions[sessID]
+	o.mutex.Unlock()
+
 	if !exist {
 		return nil, fmt.Errorf("try to get sessID sessionPool. "+
 			"SessID not found. ID: %v", sessID)
@@ -43,22 +46,42 @@ func (o *SessionPool) Put(sess ISession) error {
 			"ID: %v, Type: %v", sessID, sessType)
 	}
 
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
 	_, exist := o.sessions[sessID]
 	if exist {
 		return fmt.Errorf("session with same ID already in sessionPool. "+
 			"ID: %v, Type: %v", sessID, sessType)
 	}
 
+	o.sessions[sessID] = sess
+
+	return nil
+}
+
+// Sessions of type. All sessions if type is empty
+func (o *SessionPool) List(sessType SessionType) []ISession {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
-	o.sessions[sessID] = sess
+	res := []ISession{}
+	for _, sess := range o.sessions {
+		if sessType == "" || sess.GetType() == sessType {
+			res = append(res, sess)
+		}
+	}
 
-	return nil
+	return res
 }
 
+// Session is closed outside of lock, closing may wait for device
 func (o *SessionPool) RemoveAndClose(sessID string) error {
+	o.mutex.Lock()
 	sess, exist := o.sessions[sessID]
+	delete(o.sessions, sessID)
+	o.mutex.Unlock()
+
 	if !exist {
 		return fmt.Errorf("try to get sessID from sessionPool. "+
 			"SessID not found. ID: %v", sessID)
@@ -68,10 +91,5 @@ func (o *SessionPool) RemoveAndClose(sessID string) error {
 		sess.Close()
 	}
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
-
-	delete(o.sessions, sessID)
-
 	return nil
 }
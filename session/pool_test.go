@@ -0,0 +1,93 @@
+package session
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+type testSession struct {
+	id string
+
+	mutex   sync.Mutex
+	isClose bool
+}
+
+func (o *testSession) Connect() error { return nil }
+func (o *testSession) Command(command string, options CommandOptions) (CommandResult, error) {
+	return CommandResult{}, nil
+}
+func (o *testSession) Ping() bool           { return true }
+func (o *testSession) GetId() string        { return o.id }
+func (o *testSession) GetType() SessionType { return SessionTypeTelnet }
+
+func (o *testSession) IsClose() bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.isClose
+}
+
+func (o *testSession) Close() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.isClose = true
+}
+
+// Run with -race to check that sessions map is guarded
+func TestSessionPoolConcurrentAccess(t *testing.T) {
+	pool := NewSessionPool()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sess := &testSession{id: fmt.Sprint(i)}
+			if err := pool.Put(sess); err != nil {
+				t.Errorf("Put(). Error: %v", err)
+
+				return
+			}
+			if _, err := pool.Get(sess.id); err != nil {
+				t.Errorf("Get(). Error: %v", err)
+			}
+			pool.List("")
+			if err := pool.RemoveAndClose(sess.id); err != nil {
+				t.Errorf("RemoveAndClose(). Error: %v", err)
+			}
+			if !sess.IsClose() {
+				t.Errorf("Session is not closed. ID: %v", sess.id)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if sessions := pool.List(""); len(sessions) != 0 {
+		t.Fatalf("Sessions are not removed: %v", len(sessions))
+	}
+}
+
+func TestSessionPoolRemovesClosedSession(t *testing.T) {
+	pool := NewSessionPool()
+	sess := &testSession{id: "1"}
+
+	if err := pool.Put(sess); err != nil {
+		t.Fatalf("Put(). Error: %v", err)
+	}
+	if err := pool.Put(sess); err == nil {
+		t.Fatalf("Session with same ID is put twice")
+	}
+
+	// closed by idle timeout of session
+	sess.Close()
+
+	if _, err := pool.Get(sess.id); err == nil {
+		t.Fatalf("Closed session is returned")
+	}
+	if sessions := pool.List(""); len(sessions) != 0 {
+		t.Fatalf("Closed session is not removed: %v", len(sessions))
+	}
+}
@@ -0,0 +1,110 @@
+//go:build !windows
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const (
+	// First arg of service binary started as console exec helper
+	ConsoleExecArg = "__console-exec"
+
+	// Exit code of helper if command is not started
+	consoleExecFailedCode = 127
+)
+
+// Settings applied by helper before command is started
+type consoleExecConfig struct {
+	// -1 if service umask is inherited
+	Umask  int           `json:"umask"`
+	Limits ConsoleLimits `json:"limits"`
+	// Set if user is changed by helper instead of SysProcAttr
+	Credential *ConsoleCredential `json:"credential,omitempty"`
+}
+
+func (o *consoleExecConfig) isRequired() bool {
+	return o.Umask >= 0 || o.Limits.hasRlimits() || o.Limits.Isolate || o.Credential != nil
+}
+
+// Run console exec helper if service binary is started as helper: os.Args are
+// ConsoleExecArg, config and command. Never returns in helper. Should be called first in main
+func RunConsoleExec() {
+	if len(os.Args) < 4 || os.Args[1] != ConsoleExecArg {
+		return
+	}
+
+	var config consoleExecConfig
+	if err := json.Unmarshal([]byte(os.Args[2]), &config); err != nil {
+		exitConsoleExec(fmt.Errorf("parse config. Error: %v", err))
+	}
+
+	if err := config.apply(); err != nil {
+		exitConsoleExec(err)
+	}
+
+	path, err := exec.LookPath(os.Args[3])
+	if err != nil {
+		exitConsoleExec(err)
+	}
+
+	exitConsoleExec(syscall.Exec(path, os.Args[3:], os.Environ()))
+}
+
+// Isolation requires root, so it is done first and user is changed last
+func (o *consoleExecConfig) apply() error {
+	if o.Limits.Isolate {
+		if err := setupIsolation(); err != nil {
+			return fmt.Errorf("isolate. Error: %v", err)
+		}
+	}
+
+	rlimits := []struct {
+		resource int
+		value    uint64
+	}{
+		{syscall.RLIMIT_CPU, o.Limits.CpuSeconds},
+		{syscall.RLIMIT_AS, o.Limits.MemoryBytes},
+		{syscall.RLIMIT_NOFILE, o.Limits.OpenFiles},
+		{syscall.RLIMIT_FSIZE, o.Limits.FileSizeBytes},
+		{rlimitProcesses, o.Limits.Processes},
+	}
+
+	for _, rlimit := range rlimits {
+		if rlimit.value == 0 {
+			continue
+		}
+
+		if err := syscall.Setrlimit(rlimit.resource, newRlimit(rlimit.value)); err != nil {
+			return fmt.Errorf("set rlimit %v. Error: %v", rlimit.resource, err)
+		}
+	}
+
+	if o.Umask >= 0 {
+		syscall.Umask(o.Umask)
+	}
+
+	if o.Credential != nil {
+		if err := syscall.Setgroups([]int{}); err != nil {
+			return fmt.Errorf("set groups. Error: %v", err)
+		}
+		if err := syscall.Setgid(int(o.Credential.Gid)); err != nil {
+			return fmt.Errorf("set gid. Error: %v", err)
+		}
+		if err := syscall.Setuid(int(o.Credential.Uid)); err != nil {
+			return fmt.Errorf("set uid. Error: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Error is written to output of command
+func exitConsoleExec(err error) {
+	fmt.Fprintf(os.Stderr, "cmdproxy console exec: %v\n", err)
+	os.Exit(consoleExecFailedCode)
+}
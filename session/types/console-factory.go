@@ -0,0 +1,206 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/recording"
+	"github.com/deminds/CmdProxy/session"
+)
+
+// User and group of console commands
+type ConsoleCredential struct {
+	Uid uint32
+	Gid uint32
+}
+
+// Parse "uid:gid"
+func ParseConsoleCredential(value string) (ConsoleCredential, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return ConsoleCredential{}, fmt.Errorf("ParseConsoleCredential() Expected uid:gid. Value: %v", value)
+	}
+
+	uid, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return ConsoleCredential{}, fmt.Errorf("ParseConsoleCredential() Wrong uid. Value: %v, Error: %v", value, err)
+	}
+
+	gid, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return ConsoleCredential{}, fmt.Errorf("ParseConsoleCredential() Wrong gid. Value: %v, Error: %v", value, err)
+	}
+
+	return ConsoleCredential{Uid: uint32(uid), Gid: uint32(gid)}, nil
+}
+
+// Process settings of console session resolved from request
+type consoleProcess struct {
+	// nil if commands are run as service user
+	credential *ConsoleCredential
+	dir        string
+	// nil if service environment is inherited
+	env []string
+	// -1 if service umask is inherited
+	umask  int
+	limits ConsoleLimits
+}
+
+func NewConsoleSessionFactory(
+	idGenerator *generatorid.IDGenerator,
+	timeoutSec int,
+	allowedCredentials []ConsoleCredential,
+	limits *ConsoleLimitsPolicy,
+	recordings *recording.Store,
+	notifier session.INotifier) *ConsoleSessionFactory {
+
+	allowed := map[ConsoleCredential]bool{}
+	for _, credential := range allowedCredentials {
+		allowed[credential] = true
+	}
+
+	return &ConsoleSessionFactory{
+		idGenerator:        idGenerator,
+		timeoutSec:         timeoutSec,
+		allowedCredentials: allowed,
+		limits:             limits,
+		recordings:         recordings,
+		notifier:           notifier,
+	}
+}
+
+// Create console sessions with process settings allowed by service
+type ConsoleSessionFactory struct {
+	idGenerator *generatorid.IDGenerator
+	timeoutSec  int
+	// uid and gid allowed for run-as. Run-as is disabled if empty
+	allowedCredentials map[ConsoleCredential]bool
+	// limits by run-as user. nil if commands are not limited
+	limits *ConsoleLimitsPolicy
+	// nil if recording is disabled
+	recordings *recording.Store
+	// nil if events are not sent
+	notifier session.INotifier
+}
+
+// Check options against service policy. Options should be valid.
+// Refused run-as is sent to notifier as policy violation
+func (o *ConsoleSessionFactory) Authorize(options model.ConsoleOptions) error {
+	if !consoleProcessAttrSupported && (options.Uid != nil || options.Umask != "") {
+		return fmt.Errorf("ConsoleSessionFactory.Authorize() Run-as and umask are not supported on this platform")
+	}
+
+	if options.Uid == nil {
+		return nil
+	}
+
+	credential := ConsoleCredential{Uid: *options.Uid, Gid: *options.Gid}
+	if !o.allowedCredentials[credential] {
+		err := fmt.Errorf("ConsoleSessionFactory.Authorize() Run-as is not allowed. Uid: %v, Gid: %v", credential.Uid, credential.Gid)
+
+		if o.notifier != nil {
+			o.notifier.Notify(model.WebhookEvent{
+				Type:        model.EventPolicyViolation,
+				SessionType: string(session.SessionTypeConsole),
+				Error:       err.Error(),
+			})
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Check options depending on host, e.g. working dir exists. Options should be valid
+func (o *ConsoleSessionFactory) Validate(options model.ConsoleOptions) error {
+	_, err := resolveConsoleProcess(options)
+
+	return err
+}
+
+func (o *ConsoleSessionFactory) New(requestData model.ConnectConsoleRequest) (*ConsoleSession, error) {
+	if err := o.Authorize(requestData.ConsoleOptions); err != nil {
+		return nil, err
+	}
+
+	process, err := resolveConsoleProcess(requestData.ConsoleOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := NewConsoleSession(o.idGenerator, o.timeoutSec)
+	if err != nil {
+		return nil, err
+	}
+	process.limits = o.limits.For(process.credential)
+	sess.process = process
+
+	if o.recordings.ShouldRecord(requestData.Record) {
+		recorder, err := o.recordings.Create(sess.GetId(), string(sess.GetType()), recording.DefaultWidth, recording.DefaultHeight)
+		if err != nil {
+			return nil, fmt.Errorf("ConsoleSessionFactory.New() Create recorder. ID: %v, Error: %v", sess.GetId(), err)
+		}
+		sess.SetRecorder(recorder)
+	}
+
+	if o.notifier != nil {
+		sess.SetNotifier(o.notifier)
+	}
+
+	return sess, nil
+}
+
+func resolveConsoleProcess(options model.ConsoleOptions) (consoleProcess, error) {
+	process := consoleProcess{
+		dir: options.Dir,
+	}
+
+	umask, err := options.UmaskValue()
+	if err != nil {
+		return process, fmt.Errorf("resolveConsoleProcess() Error: %v", err)
+	}
+	process.umask = umask
+
+	if options.Uid != nil {
+		process.credential = &ConsoleCredential{Uid: *options.Uid, Gid: *options.Gid}
+	}
+
+	if options.Dir != "" {
+		info, err := os.Stat(options.Dir)
+		if err != nil {
+			return process, fmt.Errorf("resolveConsoleProcess() Working dir. Error: %v", err)
+		}
+		if !info.IsDir() {
+			return process, fmt.Errorf("resolveConsoleProcess() Working dir is not dir. Dir: %v", options.Dir)
+		}
+	}
+
+	if options.EnvMode == model.EnvModeReplace || len(options.Env) > 0 {
+		if options.EnvMode != model.EnvModeReplace {
+			process.env = os.Environ()
+		}
+
+		names := make([]string, 0, len(options.Env))
+		for name := range options.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		// the last value of duplicated variable is used by exec
+		for _, name := range names {
+			process.env = append(process.env, name+"="+options.Env[name])
+		}
+
+		if process.env == nil {
+			process.env = []string{}
+		}
+	}
+
+	return process, nil
+}
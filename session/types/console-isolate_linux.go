@@ -0,0 +1,114 @@
+package types
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	consoleIsolationSupported = true
+
+	// RLIMIT_NPROC is not defined by syscall
+	rlimitProcesses = unix.RLIMIT_NPROC
+)
+
+// Private mount, pid and network namespaces. Network namespace has only loopback which is down
+func isolateAttr(attr *syscall.SysProcAttr) {
+	attr.Cloneflags |= syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWNET
+}
+
+// Called by helper in new namespaces. Mounts are not propagated to service,
+// /proc shows processes of namespace and all mounts are remounted read-only
+func setupIsolation() error {
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("make mounts private. Error: %v", err)
+	}
+
+	if err := syscall.Mount("proc", "/proc", "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("mount proc. Error: %v", err)
+	}
+
+	mounts, err := mountPoints()
+	if err != nil {
+		return err
+	}
+
+	for _, mount := range mounts {
+		err := syscall.Mount("", mount.path, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY|mount.flags, "")
+		// mounts hidden by other mounts can not be remounted
+		if err != nil && mount.path == "/" {
+			return fmt.Errorf("remount root read-only. Error: %v", err)
+		}
+	}
+
+	return nil
+}
+
+type mountPoint struct {
+	path string
+	// flags kept on remount
+	flags uintptr
+}
+
+func mountPoints() ([]mountPoint, error) {
+	file, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return nil, fmt.Errorf("read mounts. Error: %v", err)
+	}
+	defer file.Close()
+
+	res := []mountPoint{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// device, path, type, options, ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+
+		mount := mountPoint{path: unescapeMountPath(fields[1])}
+		for _, option := range strings.Split(fields[3], ",") {
+			switch option {
+			case "nosuid":
+				mount.flags |= syscall.MS_NOSUID
+			case "nodev":
+				mount.flags |= syscall.MS_NODEV
+			case "noexec":
+				mount.flags |= syscall.MS_NOEXEC
+			}
+		}
+
+		res = append(res, mount)
+	}
+
+	return res, scanner.Err()
+}
+
+// Spaces and other special chars of path are escaped as octal \ooo
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, `\`) {
+		return path
+	}
+
+	var res strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+4 <= len(path) {
+			if code, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				res.WriteByte(byte(code))
+				i += 3
+
+				continue
+			}
+		}
+
+		res.WriteByte(path[i])
+	}
+
+	return res.String()
+}
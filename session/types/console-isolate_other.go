@@ -0,0 +1,23 @@
+//go:build !linux && !windows
+
+package types
+
+import (
+	"fmt"
+	"syscall"
+)
+
+const (
+	consoleIsolationSupported = false
+
+	// processes limit is not supported
+	rlimitProcesses = -1
+)
+
+func isolateAttr(attr *syscall.SysProcAttr) {
+}
+
+// Refused by ConsoleLimits.validate()
+func setupIsolation() error {
+	return fmt.Errorf("isolation is not supported on this platform")
+}
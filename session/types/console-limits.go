@@ -0,0 +1,118 @@
+package types
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Resource limits of console commands. Zero value is unlimited
+type ConsoleLimits struct {
+	// rlimits of every command process
+	CpuSeconds    uint64 `yaml:"cpuSeconds,omitempty" json:"cpuSeconds,omitempty"`
+	MemoryBytes   uint64 `yaml:"memoryBytes,omitempty" json:"memoryBytes,omitempty"`
+	OpenFiles     uint64 `yaml:"openFiles,omitempty" json:"openFiles,omitempty"`
+	FileSizeBytes uint64 `yaml:"fileSizeBytes,omitempty" json:"fileSizeBytes,omitempty"`
+	// Processes of the user running command. Not applied to root
+	Processes uint64 `yaml:"processes,omitempty" json:"processes,omitempty"`
+
+	// Max bytes of command output. Command is killed when limit is reached
+	OutputBytes int64 `yaml:"outputBytes,omitempty" json:"outputBytes,omitempty"`
+
+	// Run command in private mount, pid and network namespaces with read-only file system.
+	// Linux only, service should run as root
+	Isolate bool `yaml:"isolate,omitempty" json:"isolate,omitempty"`
+}
+
+func (o *ConsoleLimits) hasRlimits() bool {
+	return o.CpuSeconds > 0 || o.MemoryBytes > 0 || o.OpenFiles > 0 || o.FileSizeBytes > 0 || o.Processes > 0
+}
+
+// Limits are supported by platform
+func (o *ConsoleLimits) validate() error {
+	if !consoleProcessAttrSupported && (o.hasRlimits() || o.Isolate) {
+		return fmt.Errorf("rlimits and isolation are not supported on this platform")
+	}
+
+	if o.Processes > 0 && rlimitProcesses < 0 {
+		return fmt.Errorf("processes limit is not supported on this platform")
+	}
+
+	if o.Isolate && !consoleIsolationSupported {
+		return fmt.Errorf("isolation is not supported on this platform")
+	}
+
+	if o.OutputBytes < 0 {
+		return fmt.Errorf("outputBytes should not be negative")
+	}
+
+	return nil
+}
+
+type consoleLimitsFile struct {
+	// Limits of commands run as service user or as user without own limits
+	Default ConsoleLimits `yaml:"default"`
+	// Limits by run-as "uid:gid". Replace default limits
+	Principals map[string]ConsoleLimits `yaml:"principals"`
+}
+
+// Limits of console commands by run-as user
+type ConsoleLimitsPolicy struct {
+	defaultLimits ConsoleLimits
+	principals    map[ConsoleCredential]ConsoleLimits
+}
+
+// Load yaml file with default limits and limits of principals
+func LoadConsoleLimits(path string) (*ConsoleLimitsPolicy, error) {
+	logPrefix := "LoadConsoleLimits()"
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%v Read file. Path: %v, Error: %v", logPrefix, path, err)
+	}
+
+	var file consoleLimitsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("%v Unmarshal. Path: %v, Error: %v", logPrefix, path, err)
+	}
+
+	if err := file.Default.validate(); err != nil {
+		return nil, fmt.Errorf("%v Default limits. Path: %v, Error: %v", logPrefix, path, err)
+	}
+
+	policy := &ConsoleLimitsPolicy{
+		defaultLimits: file.Default,
+		principals:    map[ConsoleCredential]ConsoleLimits{},
+	}
+
+	for principal, limits := range file.Principals {
+		credential, err := ParseConsoleCredential(principal)
+		if err != nil {
+			return nil, fmt.Errorf("%v Path: %v, Error: %v", logPrefix, path, err)
+		}
+
+		if err := limits.validate(); err != nil {
+			return nil, fmt.Errorf("%v Limits of %v. Path: %v, Error: %v", logPrefix, principal, path, err)
+		}
+
+		policy.principals[credential] = limits
+	}
+
+	return policy, nil
+}
+
+// Limits of run-as user, nil is service user. Unlimited if policy is nil
+func (o *ConsoleLimitsPolicy) For(credential *ConsoleCredential) ConsoleLimits {
+	if o == nil {
+		return ConsoleLimits{}
+	}
+
+	if credential != nil {
+		if limits, exist := o.principals[*credential]; exist {
+			return limits
+		}
+	}
+
+	return o.defaultLimits
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Output of command limited by size. The rest of output is dropped.
+// Buffer is not embedded, otherwise its ReadFrom would be used by io.Copy instead of Write
+type limitedBuffer struct {
+	buffer bytes.Buffer
+	// 0 - unlimited
+	limit    int64
+	exceeded bool
+	// called once when limit is exceeded
+	onLimit func()
+	// called with written data which is kept
+	onWrite func(data []byte)
+}
+
+// Exec calls Write of combined output from one goroutine at a time
+func (o *limitedBuffer) Write(data []byte) (int, error) {
+	if o.limit > 0 && int64(len(data)) > o.limit-int64(o.buffer.Len()) {
+		if rest := o.limit - int64(o.buffer.Len()); rest > 0 {
+			o.write(data[:rest])
+		}
+
+		if !o.exceeded {
+			o.exceeded = true
+			if o.onLimit != nil {
+				o.onLimit()
+			}
+		}
+
+		return len(data), nil
+	}
+
+	o.write(data)
+
+	return len(data), nil
+}
+
+func (o *limitedBuffer) write(data []byte) {
+	o.buffer.Write(data)
+
+	if o.onWrite != nil {
+		o.onWrite(data)
+	}
+}
+
+// Output received before limit is valid
+type outputLimitError struct {
+	limit int64
+}
+
+func (o *outputLimitError) Error() string {
+	return fmt.Sprintf("output limit of %v bytes is reached, command is killed", o.limit)
+}
@@ -0,0 +1,77 @@
+//go:build !windows
+
+package types
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const consoleProcessAttrSupported = true
+
+// Binary of service started as console exec helper
+var consoleExecPath = executablePath()
+
+// Command of console session. Umask, rlimits and isolation can not be set by SysProcAttr,
+// so service binary is started as helper which applies them and replaces itself with command
+func (o consoleProcess) command(name string, args []string) *exec.Cmd {
+	// command and its descendants are stopped together by process group
+	attr := &syscall.SysProcAttr{Setpgid: true}
+	config := consoleExecConfig{
+		Umask:  o.umask,
+		Limits: o.limits,
+	}
+
+	if o.credential != nil {
+		// mounts of isolated command require root, so user is changed by helper after them
+		if o.limits.Isolate {
+			config.Credential = o.credential
+		} else {
+			attr.Credential = &syscall.Credential{
+				Uid: o.credential.Uid,
+				Gid: o.credential.Gid,
+				// supplementary groups of service are dropped, it requires root
+				Groups:      []uint32{},
+				NoSetGroups: os.Geteuid() != 0,
+			}
+		}
+	}
+
+	if o.limits.Isolate {
+		isolateAttr(attr)
+	}
+
+	var cmd *exec.Cmd
+	if config.isRequired() {
+		configBytes, _ := json.Marshal(config)
+		cmd = exec.Command(consoleExecPath, append([]string{ConsoleExecArg, string(configBytes), name}, args...)...)
+	} else {
+		cmd = exec.Command(name, args...)
+	}
+
+	cmd.Dir = o.dir
+	cmd.Env = o.env
+	cmd.SysProcAttr = attr
+
+	return cmd
+}
+
+// Process group id is pid of command
+func terminateGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+func killGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+func executablePath() string {
+	path, err := os.Executable()
+	if err != nil {
+		return os.Args[0]
+	}
+
+	return path
+}
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"os/exec"
+)
+
+const (
+	consoleProcessAttrSupported = false
+	consoleIsolationSupported   = false
+
+	// processes limit is not supported
+	rlimitProcesses = -1
+)
+
+// Command of console session. Run-as, umask and limits are refused by factory
+func (o consoleProcess) command(name string, args []string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = o.dir
+	cmd.Env = o.env
+
+	return cmd
+}
+
+// Process groups are not used on this platform, only command is killed
+func terminateGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+func killGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// Console exec helper is not used on this platform
+func RunConsoleExec() {
+}
@@ -0,0 +1,17 @@
+//go:build freebsd || dragonfly
+
+package types
+
+import (
+	"math"
+	"syscall"
+)
+
+// Rlimit values are signed on this OS, larger values are unlimited
+func newRlimit(value uint64) *syscall.Rlimit {
+	if value > math.MaxInt64 {
+		value = math.MaxInt64
+	}
+
+	return &syscall.Rlimit{Cur: int64(value), Max: int64(value)}
+}
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+// Time between SIGTERM and SIGKILL of stopped command. Set before sessions are created
+var ConsoleKillGrace = 5 * time.Second
+
+// Events stopping running command. nil channel is never received
+type consoleAbort struct {
+	timeout    <-chan time.Time
+	cancel     <-chan struct{}
+	disconnect <-chan struct{}
+	shutdown   <-chan struct{}
+}
+
+// Commands running in all console sessions. New commands are refused after shutdown
+var consoleRuns = struct {
+	mutex    sync.Mutex
+	closed   bool
+	shutdown chan struct{}
+	running  sync.WaitGroup
+}{
+	shutdown: make(chan struct{}),
+}
+
+// false if service is shutting down
+func beginConsoleRun() bool {
+	consoleRuns.mutex.Lock()
+	defer consoleRuns.mutex.Unlock()
+
+	if consoleRuns.closed {
+		return false
+	}
+	consoleRuns.running.Add(1)
+
+	return true
+}
+
+func endConsoleRun() {
+	consoleRuns.running.Done()
+}
+
+// Stop running commands of all console sessions and close sessions. Returns when
+// process groups of commands are stopped. Should be called on service shutdown
+func ShutdownConsoleSessions() {
+	consoleRuns.mutex.Lock()
+	if !consoleRuns.closed {
+		consoleRuns.closed = true
+		close(consoleRuns.shutdown)
+	}
+	consoleRuns.mutex.Unlock()
+
+	consoleRuns.running.Wait()
+}
+
+// Run command in own process group and return combined output and how command ended.
+// Command is stopped by abort events and if output exceeds limit. Kept output is passed to onOutput if it is set
+func runConsoleCommand(cmd *exec.Cmd, limit int64, onOutput func([]byte), abort consoleAbort) ([]byte, *model.ProcessExit, error) {
+	limitReached := make(chan struct{})
+	output := &limitedBuffer{limit: limit, onWrite: onOutput}
+	output.onLimit = func() {
+		close(limitReached)
+	}
+
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// descendants holding output pipe do not block Wait after command exit
+	cmd.WaitDelay = ConsoleKillGrace
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	reason := model.ExitReasonExited
+	var err error
+	select {
+	case err = <-done:
+	case <-abort.timeout:
+		reason = model.ExitReasonTimeout
+	case <-abort.cancel:
+		reason = model.ExitReasonCanceled
+	case <-abort.disconnect:
+		reason = model.ExitReasonDisconnect
+	case <-abort.shutdown:
+		reason = model.ExitReasonShutdown
+	case <-limitReached:
+		reason = model.ExitReasonOutput
+	}
+
+	if reason != model.ExitReasonExited {
+		err = stopConsoleCommand(cmd, done)
+	}
+
+	exit := processExit(reason, cmd.ProcessState)
+	if output.exceeded {
+		return output.buffer.Bytes(), exit, &outputLimitError{limit: limit}
+	}
+
+	return output.buffer.Bytes(), exit, err
+}
+
+// Terminate process group and kill it if command does not exit in grace period.
+// Pid 1 of isolated command ignores SIGTERM without handler, so it is killed after grace
+func stopConsoleCommand(cmd *exec.Cmd, done <-chan error) error {
+	terminateGroup(cmd)
+
+	select {
+	case err := <-done:
+		// descendants ignoring SIGTERM. Group id is not reused while they are alive
+		killGroup(cmd)
+
+		return err
+	case <-time.After(ConsoleKillGrace):
+	}
+
+	killGroup(cmd)
+
+	return <-done
+}
+
+// nil if command is not started
+func processExit(reason string, state *os.ProcessState) *model.ProcessExit {
+	if state == nil {
+		return nil
+	}
+
+	exit := &model.ProcessExit{
+		Reason: reason,
+		Code:   state.ExitCode(),
+	}
+
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		exit.Signal = status.Signal().String()
+		if reason == model.ExitReasonExited {
+			exit.Reason = model.ExitReasonSignaled
+		}
+	}
+
+	return exit
+}
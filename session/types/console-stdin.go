@@ -0,0 +1,60 @@
+package types
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+// Open input of running command. Writes are done in order of calls
+type consoleStdin struct {
+	mutex  sync.Mutex
+	writer io.WriteCloser
+	closed bool
+	// failed write of initial input, returned by later writes
+	err error
+}
+
+// Blocks while command does not read input. Fails when command exits
+func (o *consoleStdin) write(data []byte, close bool) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.err != nil {
+		return o.err
+	}
+
+	if o.closed {
+		return fmt.Errorf("stdin is closed")
+	}
+
+	if len(data) > 0 {
+		if _, err := o.writer.Write(data); err != nil {
+			return fmt.Errorf("write stdin. Error: %v", err)
+		}
+	}
+
+	if close {
+		o.closed = true
+
+		return o.writer.Close()
+	}
+
+	return nil
+}
+
+// Initial input is written in background, later writes wait for it
+func (o *consoleStdin) writeInitial(data []byte) {
+	o.mutex.Lock()
+
+	go func() {
+		defer o.mutex.Unlock()
+
+		if _, err := o.writer.Write(data); err != nil {
+			glog.Errorf("consoleStdin.writeInitial() Error: %v", err)
+			o.err = fmt.Errorf("write initial stdin. Error: %v", err)
+		}
+	}()
+}
@@ -1,12 +1,15 @@
 package types
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/recording"
 	"github.com/deminds/CmdProxy/session"
 	"github.com/golang/glog"
-	"os/exec"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -27,10 +30,12 @@ func NewConsoleSession(idGenerator *generatorid.IDGenerator, timeoutSec int) (*C
 		isClose:     false,
 		sessionType: session.SessionTypeConsole,
 		timeout:     timeoutSec,
+		process:     consoleProcess{umask: -1},
 
-		output:     make(chan string),
-		command:    make(chan string),
-		disconnect: make(chan bool),
+		output:     make(chan consoleOutput),
+		command:    make(chan consoleCommand),
+		disconnect: make(chan struct{}),
+		done:       make(chan struct{}),
 	}
 
 	glog.Infof("NewConsoleSession() ID: %v, Type: %v, Timeout: %v", sess.id, sess.sessionType, sess.timeout)
@@ -43,46 +48,135 @@ type ConsoleSession struct {
 	isClose     bool
 	sessionType session.SessionType
 	timeout     int
+	// user, dir, environment and umask of commands
+	process consoleProcess
+	// nil if session is not recorded
+	recorder *recording.Recorder
+	// nil if events are not sent
+	notifier session.INotifier
+
+	command chan consoleCommand
+	// output of every received command is sent
+	output chan consoleOutput
+	// closed by Close, running command is stopped
+	disconnect chan struct{}
+	closeOnce  sync.Once
+	// closed when session routine exits
+	done      chan struct{}
+	connected bool
+
+	// input of running command started with KeepStdinOpen. nil if input is not open
+	stdin      *consoleStdin
+	stdinMutex sync.Mutex
+}
+
+type consoleCommand struct {
+	command       string
+	cancel        <-chan struct{}
+	stdin         []byte
+	keepStdinOpen bool
+	outputChunk   func([]byte)
+}
+
+type consoleOutput struct {
+	output string
+	// nil if command is not started
+	exit *model.ProcessExit
+	// command is stopped by timeout, cancel, disconnect or shutdown
+	err error
+	// output limit is reached
+	truncated bool
+}
+
+// Record session transcript. Should be called before Connect()
+func (o *ConsoleSession) SetRecorder(recorder *recording.Recorder) {
+	o.recorder = recorder
+}
 
-	command    chan string
-	output     chan string
-	disconnect chan bool
+// Send session events to notifier. Should be called before Connect()
+func (o *ConsoleSession) SetNotifier(notifier session.INotifier) {
+	o.notifier = notifier
 }
 
 func (o *ConsoleSession) Connect() error {
 	glog.Infof("ConsoleSession.Connect() ID: %v, Type: %v", o.id, o.sessionType)
+	o.notify(model.EventSessionConnected, "", nil)
+	o.connected = true
 	go o.start()
 
 	return nil
 }
 
-func (o *ConsoleSession) Command(command string) (string, error) {
+func (o *ConsoleSession) Command(command string, options session.CommandOptions) (session.CommandResult, error) {
 	glog.Infof("ConsoleSession.Command(%v). Execute command. "+
 		"ID: %v, Type: %v", command, o.id, o.sessionType)
 
 	if o.isClose {
-		return "", fmt.Errorf("ConsoleSession.Command(%v). Session is close. "+
+		err := fmt.Errorf("ConsoleSession.Command(%v). Session is close. "+
 			"ID: %v, Type: %v", command, o.id, o.sessionType)
+		o.notify(model.EventCommandFailed, command, err)
+
+		return session.CommandResult{}, err
 	}
 
-	o.command <- command
+	if len(options.Expect) > 0 {
+		return session.CommandResult{}, fmt.Errorf("ConsoleSession.Command(%v). Expect is not supported. "+
+			"ID: %v, Type: %v", command, o.id, o.sessionType)
+	}
 
 	select {
-	case res := <-o.output:
-		glog.Infof("ConsoleSession.Command(%v). Received output. "+
-			"ID: %v, Type: %v, Output: %v", command, o.id, o.sessionType, res)
+	case o.command <- consoleCommand{
+		command:       command,
+		cancel:        options.Cancel,
+		stdin:         options.Stdin,
+		keepStdinOpen: options.KeepStdinOpen,
+		outputChunk:   options.OutputChunk,
+	}:
+	case <-o.done:
+		return session.CommandResult{}, fmt.Errorf("ConsoleSession.Command(%v). Session is close. "+
+			"ID: %v, Type: %v", command, o.id, o.sessionType)
+	}
 
-		return res, nil
-	case <-time.After(time.Duration(o.timeout) * time.Second):
-		o.isClose = true
+	res := <-o.output
+	if res.err != nil {
+		return session.CommandResult{Output: res.output, Exit: res.exit, Truncated: res.truncated}, res.err
+	}
 
-		return "", fmt.Errorf("ConsoleSession.Command(%v) Timeout wait output. "+
-			"ID: %v, Type: %v", command, o.id, o.sessionType)
+	glog.Infof("ConsoleSession.Command(%v). Received output. "+
+		"ID: %v, Type: %v, Output: %v", command, o.id, o.sessionType, res.output)
+
+	return session.CommandResult{Output: res.output, Exit: res.exit, Truncated: res.truncated}, nil
+}
+
+func (o *ConsoleSession) WriteStdin(data []byte, close bool, secret bool) error {
+	o.stdinMutex.Lock()
+	stdin := o.stdin
+	o.stdinMutex.Unlock()
+
+	if stdin == nil {
+		return fmt.Errorf("ConsoleSession.WriteStdin() No running command with open stdin. "+
+			"ID: %v, Type: %v", o.id, o.sessionType)
+	}
+
+	if err := stdin.write(data, close); err != nil {
+		return fmt.Errorf("ConsoleSession.WriteStdin() ID: %v, Type: %v, Error: %v", o.id, o.sessionType, err)
+	}
+
+	if o.recorder != nil {
+		// mask is not taken by input of other write
+		o.stdinMutex.Lock()
+		if secret {
+			o.recorder.MaskNextInput()
+		}
+		o.recorder.Input(data)
+		o.stdinMutex.Unlock()
 	}
+
+	return nil
 }
 
 func (o *ConsoleSession) Ping() bool {
-	if _, err := o.Command(PingCommand); err != nil {
+	if _, err := o.Command(PingCommand, session.CommandOptions{}); err != nil {
 		return false
 	}
 
@@ -105,10 +199,23 @@ func (o *ConsoleSession) Close() {
 	glog.Infof("ConsoleSession.Close(). ID: %v, Type: %v", o.id, o.sessionType)
 
 	o.isClose = true
-	o.disconnect <- true
+	o.closeOnce.Do(func() {
+		close(o.disconnect)
+	})
+
+	// running command is stopped before return
+	if o.connected {
+		<-o.done
+	}
 }
 
 func (o *ConsoleSession) start() {
+	defer close(o.done)
+
+	if o.recorder != nil {
+		defer o.recorder.Close()
+	}
+
 	for {
 		select {
 		case c, ok := <-o.command:
@@ -116,60 +223,174 @@ func (o *ConsoleSession) start() {
 				glog.Infof("ConsoleSession.start() Command chan was close. "+
 					"ID: %v, Type: %v", o.id, o.sessionType)
 				o.isClose = true
+				o.notify(model.EventSessionClosed, "", nil)
 
 				return
 			}
 
-			c = strings.Trim(c, CommandArgsSeparator)
-			if c == "" {
-				o.output <- EmptyCommandMsg
+			c.command = strings.Trim(c.command, CommandArgsSeparator)
+			if c.command == "" {
+				o.output <- consoleOutput{output: EmptyCommandMsg}
 
 				continue
 			}
 
 			if !o.isClose {
-				cPaths := strings.Split(c, CommandArgsSeparator)
-
-				cName := cPaths[0]
-				cArgs := []string{}
-				if len(cPaths) > 1 {
-					cArgs = cPaths[1:]
-				}
+				res := o.run(c)
+				o.output <- res
 
-				glog.Infof("exec.Command(%v, %v)", cName, cArgs)
+				if res.exit != nil && (res.exit.Reason == model.ExitReasonDisconnect || res.exit.Reason == model.ExitReasonShutdown) {
+					glog.Infof("ConsoleSession.start() Command was stopped by %v. Exit routine. "+
+						"ID: %v, Type: %v", res.exit.Reason, o.id, o.sessionType)
+					o.isClose = true
+					o.notify(model.EventSessionClosed, "", nil)
 
-				cmd := exec.Command(cName, cArgs...)
-
-				out, err := cmd.CombinedOutput()
-				outStr := string(out)
-				if err != nil {
-					// TODO: put to output struct (out, err)
-					glog.Errorf("start() cmd.Run() failed. ID: %v, Type: %v, Error: %v", o.id, o.sessionType, err)
-					outStr = err.Error()
+					return
 				}
-
-				o.output <- outStr
 			} else {
-				glog.Info("start() Session was closed. Exit routine. Id: %v, Type: %v", o.id, o.sessionType)
+				glog.Infof("start() Session was closed. Exit routine. Id: %v, Type: %v", o.id, o.sessionType)
+				o.output <- consoleOutput{err: fmt.Errorf("ConsoleSession.Command(%v). Session is close. "+
+					"ID: %v, Type: %v", c.command, o.id, o.sessionType)}
+				o.notify(model.EventSessionClosed, "", nil)
 
 				return
 			}
 
-		case d := <-o.disconnect:
-			if d {
-				glog.Infof("ConsoleSession.start() Received disconnect request. "+
-					"ID: %v, Type: %v", o.id, o.sessionType)
-				o.isClose = true
+		case <-o.disconnect:
+			glog.Infof("ConsoleSession.start() Received disconnect request. "+
+				"ID: %v, Type: %v", o.id, o.sessionType)
+			o.isClose = true
+			o.notify(model.EventSessionClosed, "", nil)
 
-				return
-			}
+			return
+
+		case <-consoleRuns.shutdown:
+			glog.Infof("ConsoleSession.start() Service shutdown. "+
+				"ID: %v, Type: %v", o.id, o.sessionType)
+			o.isClose = true
+			o.notify(model.EventSessionClosed, "", nil)
+
+			return
 
 		case <-time.After(time.Duration(o.timeout) * time.Second):
 			glog.Infof("ConsoleSession.start() Timeout between commands was reach. Drop session. "+
 				"ID: %v, Type: %v", o.id, o.sessionType)
 			o.isClose = true
+			o.notify(model.EventSessionTimeout, "", nil)
 
 			return
 		}
 	}
 }
+
+// Run command in own process group. Command is stopped by session timeout, cancel of
+// command, disconnect and service shutdown
+func (o *ConsoleSession) run(c consoleCommand) consoleOutput {
+	cPaths := strings.Split(c.command, CommandArgsSeparator)
+
+	cName := cPaths[0]
+	cArgs := []string{}
+	if len(cPaths) > 1 {
+		cArgs = cPaths[1:]
+	}
+
+	if !beginConsoleRun() {
+		return consoleOutput{
+			exit: &model.ProcessExit{Reason: model.ExitReasonShutdown, Code: -1},
+			err: fmt.Errorf("ConsoleSession.Command(%v). Service shutdown. "+
+				"ID: %v, Type: %v", c.command, o.id, o.sessionType),
+		}
+	}
+	defer endConsoleRun()
+
+	// input written while command runs is recorded after command
+	if o.recorder != nil {
+		o.recorder.Input([]byte(c.command + "\n"))
+	}
+
+	glog.Infof("exec.Command(%v, %v)", cName, cArgs)
+
+	cmd := o.process.command(cName, cArgs)
+
+	if c.keepStdinOpen {
+		pipe, err := cmd.StdinPipe()
+		if err != nil {
+			return consoleOutput{output: err.Error()}
+		}
+
+		stdin := &consoleStdin{writer: pipe}
+		if len(c.stdin) > 0 {
+			stdin.writeInitial(c.stdin)
+		}
+
+		o.setStdin(stdin)
+		defer o.setStdin(nil)
+	} else if c.stdin != nil {
+		cmd.Stdin = bytes.NewReader(c.stdin)
+	}
+
+	timer := time.NewTimer(time.Duration(o.timeout) * time.Second)
+	defer timer.Stop()
+
+	out, exit, err := runConsoleCommand(cmd, o.process.limits.OutputBytes, c.outputChunk, consoleAbort{
+		timeout:    timer.C,
+		cancel:     c.cancel,
+		disconnect: o.disconnect,
+		shutdown:   consoleRuns.shutdown,
+	})
+
+	res := consoleOutput{
+		output: string(out),
+		exit:   exit,
+	}
+	if err != nil {
+		glog.Errorf("start() cmd.Run() failed. ID: %v, Type: %v, Exit: %+v, Error: %v", o.id, o.sessionType, exit, err)
+		if _, ok := err.(*outputLimitError); ok {
+			res.truncated = true
+			res.output += "\n" + err.Error()
+		} else {
+			res.output = err.Error()
+		}
+	}
+
+	if exit != nil && exit.Reason != model.ExitReasonExited && exit.Reason != model.ExitReasonSignaled && exit.Reason != model.ExitReasonOutput {
+		res.err = fmt.Errorf("ConsoleSession.Command(%v) Command is stopped. "+
+			"ID: %v, Type: %v, Reason: %v, Signal: %v", c.command, o.id, o.sessionType, exit.Reason, exit.Signal)
+		err = res.err
+	}
+
+	if err != nil {
+		o.notify(model.EventCommandFailed, c.command, err)
+	}
+
+	if o.recorder != nil {
+		o.recorder.Output([]byte(res.output))
+	}
+
+	return res
+}
+
+func (o *ConsoleSession) setStdin(stdin *consoleStdin) {
+	o.stdinMutex.Lock()
+	defer o.stdinMutex.Unlock()
+
+	o.stdin = stdin
+}
+
+func (o *ConsoleSession) notify(eventType model.EventType, command string, err error) {
+	if o.notifier == nil {
+		return
+	}
+
+	event := model.WebhookEvent{
+		Type:        eventType,
+		SessionId:   o.id,
+		SessionType: string(o.sessionType),
+		Command:     command,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	o.notifier.Notify(event)
+}
@@ -0,0 +1,226 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/recording"
+	"github.com/deminds/CmdProxy/session"
+	"github.com/golang/glog"
+)
+
+func NewSharedTelnetSession(idGenerator *generatorid.IDGenerator, timeoutSec int, devicePool *TelnetDevicePool, requestData model.ConnectTelnetRequest) (*SharedTelnetSession, error) {
+	id, err := idGenerator.Next()
+	if err != nil {
+		return nil, fmt.Errorf("NewSharedTelnetSession(). Generate id. Error: %v", err)
+	}
+
+	sess := &SharedTelnetSession{
+		id:          id,
+		isClose:     false,
+		sessionType: session.SessionTypeTelnet,
+		timeout:     time.Duration(timeoutSec) * time.Second,
+
+		devicePool:  devicePool,
+		requestData: requestData,
+
+		activity: make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+
+	glog.Infof("NewSharedTelnetSession() Host: %v, Port: %v, ID: %v, Type: %v, Timeout: %v",
+		requestData.Host, requestData.Port, sess.id, sess.sessionType, sess.timeout)
+
+	return sess, nil
+}
+
+// API session which queues commands onto telnet connections shared by device
+type SharedTelnetSession struct {
+	id          string
+	isClose     bool
+	sessionType session.SessionType
+	// session is closed if there are no commands during timeout
+	timeout time.Duration
+
+	devicePool  *TelnetDevicePool
+	requestData model.ConnectTelnetRequest
+	device      *telnetDevice
+	// nil if session is not recorded
+	recorder *recording.Recorder
+
+	mutex sync.Mutex
+	// commands which are queued or executed now
+	running int
+	// command is started or finished
+	activity chan struct{}
+	// closed on Close()
+	done chan struct{}
+}
+
+// Connections are shared with other sessions, so commands and results of this session
+// are recorded instead of connection stream
+func (o *SharedTelnetSession) SetRecorder(recorder *recording.Recorder) {
+	o.recorder = recorder
+}
+
+func (o *SharedTelnetSession) Connect() error {
+	logPrefix := "SharedTelnetSession.Connect()"
+
+	device, err := o.devicePool.attach(o.requestData)
+	if err != nil {
+		o.isClose = true
+
+		return fmt.Errorf("%v Attach to device. ID: %v, Error: %v", logPrefix, o.id, err)
+	}
+
+	if err := device.connect(); err != nil {
+		o.isClose = true
+		o.devicePool.detach(device)
+
+		return fmt.Errorf("%v Connect device. ID: %v, Error: %v", logPrefix, o.id, err)
+	}
+
+	o.device = device
+
+	go o.watchIdle()
+
+	glog.Infof("%v ID: %v, Device: %v", logPrefix, o.id, device.key)
+
+	return nil
+}
+
+func (o *SharedTelnetSession) Command(command string, options session.CommandOptions) (session.CommandResult, error) {
+	logPrefix := "SharedTelnetSession.Command()"
+
+	if o.IsClose() {
+		return session.CommandResult{}, fmt.Errorf("%v Session is close. "+
+			"ID: %v, Type: %v, Command: %v", logPrefix, o.id, o.sessionType, command)
+	}
+
+	if options.Stdin != nil || options.KeepStdinOpen {
+		return session.CommandResult{}, fmt.Errorf("%v Stdin is not supported. "+
+			"ID: %v, Type: %v, Command: %v", logPrefix, o.id, o.sessionType, command)
+	}
+
+	o.setRunning(1)
+	defer o.setRunning(-1)
+
+	job := &telnetJob{
+		command: command,
+		options: options,
+		result:  make(chan telnetJobResult, 1),
+	}
+
+	if err := o.device.enqueue(o.id, job); err != nil {
+		return session.CommandResult{}, fmt.Errorf("%v Enqueue command. ID: %v, Error: %v", logPrefix, o.id, err)
+	}
+
+	select {
+	case res := <-job.result:
+		if o.recorder != nil {
+			o.recorder.Input([]byte(command + "\r\n"))
+			o.recorder.Output([]byte(res.res.Output + "\r\n" + res.res.Prompt))
+		}
+
+		return res.res, res.err
+	case <-options.Cancel:
+		// job which is already executed is not interrupted, its result is dropped
+		o.device.drop(o.id, job)
+
+		return session.CommandResult{}, fmt.Errorf("%v Command is canceled. ID: %v, Command: %v", logPrefix, o.id, command)
+	}
+}
+
+func (o *SharedTelnetSession) Ping() bool {
+	if _, err := o.Command(PingCommand, session.CommandOptions{}); err != nil {
+		return false
+	}
+
+	return true
+}
+
+func (o *SharedTelnetSession) GetId() string {
+	return o.id
+}
+
+func (o *SharedTelnetSession) GetType() session.SessionType {
+	return o.sessionType
+}
+
+func (o *SharedTelnetSession) IsClose() bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.isClose
+}
+
+func (o *SharedTelnetSession) Close() {
+	glog.Infof("SharedTelnetSession.Close(). ID: %v, Type: %v", o.id, o.sessionType)
+
+	o.mutex.Lock()
+	if o.isClose {
+		o.mutex.Unlock()
+
+		return
+	}
+	o.isClose = true
+	close(o.done)
+	o.mutex.Unlock()
+
+	o.device.cancel(o.id)
+	o.devicePool.detach(o.device)
+
+	if o.recorder != nil {
+		o.recorder.Close()
+	}
+}
+
+func (o *SharedTelnetSession) setRunning(delta int) {
+	o.mutex.Lock()
+	o.running += delta
+	o.mutex.Unlock()
+
+	select {
+	case o.activity <- struct{}{}:
+	default:
+	}
+}
+
+// Close session after timeout between commands like TelnetSession does.
+// Shared connections are kept by device while other sessions use it
+func (o *SharedTelnetSession) watchIdle() {
+	for {
+		select {
+		case <-o.activity:
+		case <-o.done:
+			return
+		case <-time.After(o.timeout):
+			o.mutex.Lock()
+			running := o.running
+			o.mutex.Unlock()
+
+			if running > 0 {
+				continue
+			}
+
+			glog.Infof("SharedTelnetSession.watchIdle() Timeout between commands was reach. Drop session. "+
+				"ID: %v, Type: %v", o.id, o.sessionType)
+			o.Close()
+
+			if o.devicePool.notifier != nil {
+				o.devicePool.notifier.Notify(model.WebhookEvent{
+					Type:        model.EventSessionTimeout,
+					SessionId:   o.id,
+					SessionType: string(o.sessionType),
+					Host:        o.requestData.Host,
+					Port:        o.requestData.Port,
+				})
+			}
+
+			return
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/session"
+	"github.com/golang/glog"
+)
+
+func NewTelnetDevicePool(idGenerator *generatorid.IDGenerator, timeoutSec int, maxConnections int, notifier session.INotifier) *TelnetDevicePool {
+	return &TelnetDevicePool{
+		idGenerator:    idGenerator,
+		timeoutSec:     timeoutSec,
+		maxConnections: maxConnections,
+		notifier:       notifier,
+
+		devices: map[string]*telnetDevice{},
+		mutex:   sync.Mutex{},
+	}
+}
+
+// Keep authenticated telnet connections shared between API sessions.
+// Devices are keyed by host, port and login
+type TelnetDevicePool struct {
+	idGenerator    *generatorid.IDGenerator
+	timeoutSec     int
+	maxConnections int
+	notifier       session.INotifier
+
+	devices map[string]*telnetDevice
+	mutex   sync.Mutex
+}
+
+func (o *TelnetDevicePool) attach(requestData model.ConnectTelnetRequest) (*telnetDevice, error) {
+	key := fmt.Sprintf("%v:%v:%v", requestData.Host, requestData.Port, requestData.Login)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	device, exist := o.devices[key]
+	if exist {
+		// do not allow to use connection without knowing credentials
+		if device.request.Password != requestData.Password ||
+			device.request.EnablePassword != requestData.EnablePassword {
+
+			err := fmt.Errorf("TelnetDevicePool.attach() Credentials do not match shared connection. Key: %v", key)
+			if o.notifier != nil {
+				o.notifier.Notify(model.WebhookEvent{
+					Type:        model.EventPolicyViolation,
+					SessionType: string(session.SessionTypeTelnet),
+					Host:        requestData.Host,
+					Port:        requestData.Port,
+					Error:       err.Error(),
+				})
+			}
+
+			return nil, err
+		}
+
+		device.users++
+
+		return device, nil
+	}
+
+	maxConnections := o.maxConnections
+	if requestData.MaxConnections > 0 {
+		maxConnections = requestData.MaxConnections
+	}
+	if maxConnections < 1 {
+		maxConnections = 1
+	}
+
+	device = &telnetDevice{
+		key:            key,
+		request:        requestData,
+		pool:           o,
+		maxConnections: maxConnections,
+		users:          1,
+
+		queues: map[string][]*telnetJob{},
+	}
+	device.cond = sync.NewCond(&device.mutex)
+	o.devices[key] = device
+
+	glog.Infof("TelnetDevicePool.attach() New shared device. Key: %v, MaxConnections: %v", key, maxConnections)
+
+	return device, nil
+}
+
+func (o *TelnetDevicePool) detach(device *telnetDevice) {
+	o.mutex.Lock()
+	device.users--
+	last := device.users <= 0
+	if last {
+		delete(o.devices, device.key)
+	}
+	o.mutex.Unlock()
+
+	if last {
+		glog.Infof("TelnetDevicePool.detach() Last user detached. Close device. Key: %v", device.key)
+		device.close()
+	}
+}
+
+type telnetJob struct {
+	command string
+	options session.CommandOptions
+	result  chan telnetJobResult
+}
+
+// Client of job went away before job is started
+func (o *telnetJob) isCanceled() bool {
+	select {
+	case <-o.options.Cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+type telnetJobResult struct {
+	res session.CommandResult
+	err error
+}
+
+// Shared device. Jobs of API sessions are queued separately and served round-robin
+// by at most maxConnections telnet connections
+type telnetDevice struct {
+	key            string
+	request        model.ConnectTelnetRequest
+	pool           *TelnetDevicePool
+	maxConnections int
+	users          int
+
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	queues  map[string][]*telnetJob
+	order   []string
+	workers int
+	idle    int
+	closed  bool
+}
+
+// Connect first telnet connection. Check credentials and expected strings of device
+func (o *telnetDevice) connect() error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.workers > 0 {
+		return nil
+	}
+
+	sess, err := o.newConnection()
+	if err != nil {
+		return err
+	}
+
+	o.startWorker(sess)
+
+	return nil
+}
+
+func (o *telnetDevice) enqueue(sessID string, job *telnetJob) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.closed {
+		return fmt.Errorf("telnetDevice.enqueue() Device is closed. Key: %v", o.key)
+	}
+
+	if _, exist := o.queues[sessID]; !exist {
+		o.order = append(o.order, sessID)
+	}
+	o.queues[sessID] = append(o.queues[sessID], job)
+
+	if o.idle == 0 && o.workers < o.maxConnections {
+		o.startWorker(nil)
+	}
+
+	o.cond.Signal()
+
+	return nil
+}
+
+// Drop queued jobs of API session
+func (o *telnetDevice) cancel(sessID string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for _, job := range o.queues[sessID] {
+		job.result <- telnetJobResult{err: fmt.Errorf("telnetDevice.cancel() Session is closed. ID: %v", sessID)}
+	}
+	o.removeQueue(sessID)
+}
+
+// Remove job of API session from queue if it is not taken by worker yet
+func (o *telnetDevice) drop(sessID string, job *telnetJob) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	jobs := o.queues[sessID]
+	for idx, queued := range jobs {
+		if queued != job {
+			continue
+		}
+
+		if len(jobs) == 1 {
+			o.removeQueue(sessID)
+		} else {
+			o.queues[sessID] = append(jobs[:idx:idx], jobs[idx+1:]...)
+		}
+
+		glog.Infof("telnetDevice.drop() Queued job is canceled. Key: %v, ID: %v, Command: %v", o.key, sessID, job.command)
+
+		return
+	}
+}
+
+func (o *telnetDevice) close() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.closed = true
+	for sessID, jobs := range o.queues {
+		for _, job := range jobs {
+			job.result <- telnetJobResult{err: fmt.Errorf("telnetDevice.close() Device is closed. Key: %v", o.key)}
+		}
+		o.removeQueue(sessID)
+	}
+
+	o.cond.Broadcast()
+}
+
+// Should be called under mutex
+func (o *telnetDevice) startWorker(sess *TelnetSession) {
+	o.workers++
+
+	go o.work(sess)
+}
+
+func (o *telnetDevice) work(sess *TelnetSession) {
+	logPrefix := "telnetDevice.work()"
+
+	defer func() {
+		if sess != nil && !sess.IsClose() {
+			sess.Close()
+		}
+
+		o.mutex.Lock()
+		o.workers--
+		o.mutex.Unlock()
+	}()
+
+	for {
+		job := o.next()
+		if job == nil {
+			glog.Infof("%v Device is closed. Exit worker. Key: %v", logPrefix, o.key)
+
+			return
+		}
+
+		if job.isCanceled() {
+			continue
+		}
+
+		// connection is dropped by idle timeout, reconnect
+		if sess == nil || sess.IsClose() {
+			var err error
+			sess, err = o.newConnection()
+			if err != nil {
+				job.result <- telnetJobResult{err: err}
+
+				continue
+			}
+		}
+
+		res, err := sess.Command(job.command, job.options)
+		job.result <- telnetJobResult{res: res, err: err}
+	}
+}
+
+// Wait next job. API sessions are served round-robin. Return nil if device is closed
+func (o *telnetDevice) next() *telnetJob {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.idle++
+	defer func() { o.idle-- }()
+
+	for len(o.order) == 0 && !o.closed {
+		o.cond.Wait()
+	}
+
+	if o.closed {
+		return nil
+	}
+
+	sessID := o.order[0]
+	jobs := o.queues[sessID]
+	job := jobs[0]
+
+	o.removeQueue(sessID)
+	if len(jobs) > 1 {
+		o.queues[sessID] = jobs[1:]
+		o.order = append(o.order, sessID)
+	}
+
+	return job
+}
+
+// Should be called under mutex
+func (o *telnetDevice) removeQueue(sessID string) {
+	delete(o.queues, sessID)
+
+	for idx, id := range o.order {
+		if id == sessID {
+			o.order = append(o.order[:idx], o.order[idx+1:]...)
+
+			break
+		}
+	}
+}
+
+func (o *telnetDevice) newConnection() (*TelnetSession, error) {
+	sess, err := NewTelnetSession(o.pool.idGenerator, o.pool.timeoutSec, o.request)
+	if err != nil {
+		return nil, fmt.Errorf("telnetDevice.newConnection() NewTelnetSession(). Key: %v, Error: %v", o.key, err)
+	}
+
+	if o.pool.notifier != nil {
+		sess.SetNotifier(o.pool.notifier)
+	}
+
+	if err := sess.Connect(); err != nil {
+		return nil, fmt.Errorf("telnetDevice.newConnection() sess.Connect(). Key: %v, Error: %v", o.key, err)
+	}
+
+	glog.Infof("telnetDevice.newConnection() Key: %v, Connection ID: %v", o.key, sess.GetId())
+
+	return sess, nil
+}
@@ -0,0 +1,40 @@
+package types
+
+import "testing"
+
+func TestCompilePromptLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		literal  bool
+		line     string
+		match    bool
+	}{
+		{name: "literal", expected: "user@host:~$", literal: true, line: "user@host:~$ ", match: true},
+		{name: "literal is not regexp", expected: "sw1.lab#", literal: true, line: "sw1-lab#", match: false},
+		{name: "literal at begin of line", expected: "sw1#", literal: true, line: "ping sw1#", match: false},
+		{name: "regexp", expected: `user@host:~\$`, literal: false, line: "user@host:~$ ", match: true},
+		{name: "regexp end of line", expected: "user@host:~$", literal: false, line: "user@host:~$ ", match: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			re, err := compilePrompt(test.expected, test.literal)
+			if err != nil {
+				t.Fatalf("compilePrompt(). Error: %v", err)
+			}
+
+			if re.MatchString(test.line) != test.match {
+				t.Fatalf("Wrong match. Regexp: %v, Line: %q, Expected: %v", re, test.line, test.match)
+			}
+		})
+	}
+
+	// unbalanced parenthesis is valid literal only
+	if _, err := compileExpected("host(config", true); err != nil {
+		t.Fatalf("Literal is not compiled. Error: %v", err)
+	}
+	if _, err := compileExpected("host(config", false); err == nil {
+		t.Fatalf("Wrong regexp is compiled")
+	}
+}
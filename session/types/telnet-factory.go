@@ -0,0 +1,122 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/recording"
+	"github.com/deminds/CmdProxy/session"
+)
+
+func NewTelnetSessionFactory(
+	idGenerator *generatorid.IDGenerator,
+	timeoutSec int,
+	terminal TelnetTerminal,
+	devicePool *TelnetDevicePool,
+	recordings *recording.Store,
+	notifier session.INotifier) *TelnetSessionFactory {
+
+	return &TelnetSessionFactory{
+		idGenerator: idGenerator,
+		timeoutSec:  timeoutSec,
+		terminal:    terminal,
+		devicePool:  devicePool,
+		recordings:  recordings,
+		notifier:    notifier,
+	}
+}
+
+// Create telnet sessions with service defaults applied
+type TelnetSessionFactory struct {
+	idGenerator *generatorid.IDGenerator
+	timeoutSec  int
+	// default terminal settings
+	terminal   TelnetTerminal
+	devicePool *TelnetDevicePool
+	// nil if recording is disabled
+	recordings *recording.Store
+	// nil if events are not sent
+	notifier session.INotifier
+}
+
+func (o *TelnetSessionFactory) New(requestData model.ConnectTelnetRequest) (session.ISession, error) {
+	if requestData.TerminalType == "" {
+		requestData.TerminalType = o.terminal.Type
+	}
+	if requestData.TerminalWidth == 0 {
+		requestData.TerminalWidth = o.terminal.Width
+	}
+	if requestData.TerminalHeight == 0 {
+		requestData.TerminalHeight = o.terminal.Height
+	}
+
+	// connections of shared session belong to device, so API session records its commands
+	if requestData.Shared {
+		sess, err := NewSharedTelnetSession(o.idGenerator, o.timeoutSec, o.devicePool, requestData)
+		if err != nil {
+			return nil, err
+		}
+
+		recorder, err := o.newRecorder(sess.GetId(), requestData)
+		if err != nil {
+			return nil, err
+		}
+		if recorder != nil {
+			sess.SetRecorder(recorder)
+		}
+
+		return sess, nil
+	}
+
+	sess, err := NewTelnetSession(o.idGenerator, o.timeoutSec, requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder, err := o.newRecorder(sess.GetId(), requestData)
+	if err != nil {
+		return nil, err
+	}
+	if recorder != nil {
+		sess.SetRecorder(recorder)
+	}
+
+	if o.notifier != nil {
+		sess.SetNotifier(o.notifier)
+	}
+
+	return sess, nil
+}
+
+// Nil if session is not recorded
+func (o *TelnetSessionFactory) newRecorder(sessID string, requestData model.ConnectTelnetRequest) (*recording.Recorder, error) {
+	if !o.recordings.ShouldRecord(requestData.Record) {
+		return nil, nil
+	}
+
+	title := fmt.Sprintf("%v:%v", requestData.Host, requestData.Port)
+	recorder, err := o.recordings.Create(sessID, title, recordingWidth(requestData.TerminalWidth), recordingHeight(requestData.TerminalHeight))
+	if err != nil {
+		return nil, fmt.Errorf("TelnetSessionFactory.New() Create recorder. ID: %v, Error: %v", sessID, err)
+	}
+
+	return recorder, nil
+}
+
+// Asciicast requires terminal size. Unlimited telnet size is recorded as default one
+func recordingWidth(width int) int {
+	if width <= 0 {
+		return recording.DefaultWidth
+	}
+
+	return width
+}
+
+func recordingHeight(height int) int {
+	if height <= 0 {
+		return recording.DefaultHeight
+	}
+
+	return height
+}
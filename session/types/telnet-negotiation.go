@@ -0,0 +1,331 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+// Telnet commands and options (RFC 854, 857, 858, 1073, 1091)
+const (
+	cmdSE   byte = 240
+	cmdSB   byte = 250
+	cmdWILL byte = 251
+	cmdWONT byte = 252
+	cmdDO   byte = 253
+	cmdDONT byte = 254
+	cmdIAC  byte = 255
+
+	optEcho            byte = 1
+	optSuppressGoAhead byte = 3
+	optTerminalType    byte = 24
+	optWindowSize      byte = 31
+
+	terminalTypeIs   byte = 0
+	terminalTypeSend byte = 1
+
+	maxSubnegotiationSize = 1024
+)
+
+type negotiationState int
+
+const (
+	stateData negotiationState = iota
+	stateIAC
+	stateOption
+	stateSubnegotiation
+	stateSubnegotiationIAC
+)
+
+type TelnetTerminal struct {
+	Type   string
+	Width  int
+	Height int
+}
+
+// Wrap connection and negotiate telnet options before data reach telnet.Conn.
+// Supported options: NAWS, TERMINAL-TYPE, ECHO (server side) and SUPPRESS-GO-AHEAD.
+// Other options are refused
+func newTelnetNegotiator(conn net.Conn, terminal TelnetTerminal) (*telnetNegotiator, error) {
+	o := &telnetNegotiator{
+		Conn:     conn,
+		terminal: terminal,
+
+		local:     map[byte]bool{},
+		remote:    map[byte]bool{},
+		offered:   map[byte]bool{},
+		requested: map[byte]bool{},
+	}
+
+	// some devices will not show login prompt until terminal type is known
+	for _, opt := range []byte{optTerminalType, optWindowSize, optSuppressGoAhead} {
+		o.offered[opt] = true
+		if err := o.send(cmdIAC, cmdWILL, opt); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, opt := range []byte{optSuppressGoAhead, optEcho} {
+		o.requested[opt] = true
+		if err := o.send(cmdIAC, cmdDO, opt); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+type telnetNegotiator struct {
+	net.Conn
+
+	terminal TelnetTerminal
+
+	state   negotiationState
+	command byte
+	subneg  []byte
+
+	// data ready for telnet.Conn and error of read it came with
+	pending []byte
+	readErr error
+
+	// options enabled on our side and on server side
+	local  map[byte]bool
+	remote map[byte]bool
+	// options we sent WILL/DO for and wait answer
+	offered   map[byte]bool
+	requested map[byte]bool
+
+	writeMutex sync.Mutex
+}
+
+// Read data and drop negotiation. Escaped IAC IAC is passed as is to telnet.Conn
+func (o *telnetNegotiator) Read(buf []byte) (int, error) {
+	for len(o.pending) == 0 {
+		// error is returned after data received with it
+		if o.readErr != nil {
+			err := o.readErr
+			o.readErr = nil
+
+			return 0, err
+		}
+
+		raw := make([]byte, len(buf))
+		n, err := o.Conn.Read(raw)
+		o.readErr = err
+
+		for _, b := range raw[:n] {
+			data, ok, err := o.handle(b)
+			if err != nil {
+				return 0, err
+			}
+
+			if ok {
+				o.pending = append(o.pending, data)
+				// keep escaping for telnet.Conn
+				if data == cmdIAC {
+					o.pending = append(o.pending, cmdIAC)
+				}
+			}
+		}
+	}
+
+	n := copy(buf, o.pending)
+	o.pending = o.pending[n:]
+
+	return n, nil
+}
+
+func (o *telnetNegotiator) Write(buf []byte) (int, error) {
+	o.writeMutex.Lock()
+	defer o.writeMutex.Unlock()
+
+	return o.Conn.Write(buf)
+}
+
+// Process one byte of input. Return data byte and true if it's not a part of negotiation
+func (o *telnetNegotiator) handle(b byte) (byte, bool, error) {
+	switch o.state {
+	case stateData:
+		if b == cmdIAC {
+			o.state = stateIAC
+
+			return 0, false, nil
+		}
+
+		return b, true, nil
+
+	case stateIAC:
+		switch b {
+		case cmdIAC:
+			o.state = stateData
+
+			return cmdIAC, true, nil
+		case cmdWILL, cmdWONT, cmdDO, cmdDONT:
+			o.command = b
+			o.state = stateOption
+		case cmdSB:
+			o.subneg = o.subneg[:0]
+			o.state = stateSubnegotiation
+		default:
+			// GA, NOP and others have no meaning for us
+			o.state = stateData
+		}
+
+		return 0, false, nil
+
+	case stateOption:
+		o.state = stateData
+
+		return 0, false, o.negotiate(o.command, b)
+
+	case stateSubnegotiation:
+		if b == cmdIAC {
+			o.state = stateSubnegotiationIAC
+		} else if len(o.subneg) < maxSubnegotiationSize {
+			o.subneg = append(o.subneg, b)
+		}
+
+		return 0, false, nil
+
+	case stateSubnegotiationIAC:
+		switch b {
+		case cmdSE:
+			o.state = stateData
+
+			return 0, false, o.subnegotiate(o.subneg)
+		case cmdIAC:
+			o.subneg = append(o.subneg, cmdIAC)
+		}
+		o.state = stateSubnegotiation
+
+		return 0, false, nil
+	}
+
+	return 0, false, fmt.Errorf("telnetNegotiator.handle() Unknown state: %v", o.state)
+}
+
+func (o *telnetNegotiator) negotiate(command, opt byte) error {
+	glog.Infof("telnetNegotiator.negotiate() Received: %v %v", command, opt)
+
+	switch command {
+	case cmdDO:
+		if !o.isLocalSupported(opt) {
+			return o.send(cmdIAC, cmdWONT, opt)
+		}
+
+		if o.local[opt] {
+			return nil
+		}
+		o.local[opt] = true
+
+		if !o.offered[opt] {
+			if err := o.send(cmdIAC, cmdWILL, opt); err != nil {
+				return err
+			}
+		}
+		delete(o.offered, opt)
+
+		if opt == optWindowSize {
+			return o.sendWindowSize()
+		}
+
+	case cmdDONT:
+		delete(o.offered, opt)
+		if o.local[opt] {
+			o.local[opt] = false
+
+			return o.send(cmdIAC, cmdWONT, opt)
+		}
+
+	case cmdWILL:
+		if !o.isRemoteSupported(opt) {
+			return o.send(cmdIAC, cmdDONT, opt)
+		}
+
+		if o.remote[opt] {
+			return nil
+		}
+		o.remote[opt] = true
+
+		if !o.requested[opt] {
+			if err := o.send(cmdIAC, cmdDO, opt); err != nil {
+				return err
+			}
+		}
+		delete(o.requested, opt)
+
+	case cmdWONT:
+		delete(o.requested, opt)
+		if o.remote[opt] {
+			o.remote[opt] = false
+
+			return o.send(cmdIAC, cmdDONT, opt)
+		}
+	}
+
+	return nil
+}
+
+func (o *telnetNegotiator) subnegotiate(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if data[0] == optTerminalType && len(data) > 1 && data[1] == terminalTypeSend {
+		glog.Infof("telnetNegotiator.subnegotiate() Send terminal type: %v", o.terminal.Type)
+
+		msg := []byte{cmdIAC, cmdSB, optTerminalType, terminalTypeIs}
+		msg = append(msg, escapeIAC([]byte(o.terminal.Type))...)
+		msg = append(msg, cmdIAC, cmdSE)
+
+		return o.send(msg...)
+	}
+
+	return nil
+}
+
+func (o *telnetNegotiator) sendWindowSize() error {
+	glog.Infof("telnetNegotiator.sendWindowSize() Width: %v, Height: %v", o.terminal.Width, o.terminal.Height)
+
+	size := []byte{
+		byte(o.terminal.Width >> 8), byte(o.terminal.Width),
+		byte(o.terminal.Height >> 8), byte(o.terminal.Height),
+	}
+
+	msg := []byte{cmdIAC, cmdSB, optWindowSize}
+	msg = append(msg, escapeIAC(size)...)
+	msg = append(msg, cmdIAC, cmdSE)
+
+	return o.send(msg...)
+}
+
+func (o *telnetNegotiator) isLocalSupported(opt byte) bool {
+	return opt == optTerminalType || opt == optWindowSize || opt == optSuppressGoAhead
+}
+
+// device echoes our input, we never echo device output
+func (o *telnetNegotiator) isRemoteSupported(opt byte) bool {
+	return opt == optEcho || opt == optSuppressGoAhead
+}
+
+func (o *telnetNegotiator) send(msg ...byte) error {
+	if _, err := o.Write(msg); err != nil {
+		return fmt.Errorf("telnetNegotiator.send() Write: %v, Error: %v", msg, err)
+	}
+
+	return nil
+}
+
+func escapeIAC(data []byte) []byte {
+	res := make([]byte, 0, len(data))
+	for _, b := range data {
+		res = append(res, b)
+		if b == cmdIAC {
+			res = append(res, cmdIAC)
+		}
+	}
+
+	return res
+}
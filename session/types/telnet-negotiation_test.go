@@ -0,0 +1,208 @@
+package types
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+const testUnknownOption byte = 99
+
+// Fake telnet server connected to negotiator. Data passed by negotiator is sent to data channel
+type fakeTelnetServer struct {
+	t    *testing.T
+	conn net.Conn
+	data chan []byte
+}
+
+func newFakeTelnetServer(t *testing.T, terminal TelnetTerminal) *fakeTelnetServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen. Error: %v", err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(accepted)
+
+			return
+		}
+		accepted <- conn
+	}()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial. Error: %v", err)
+	}
+
+	serverConn, ok := <-accepted
+	if !ok {
+		t.Fatalf("Accept failed")
+	}
+
+	server := &fakeTelnetServer{
+		t:    t,
+		conn: serverConn,
+		data: make(chan []byte, 100),
+	}
+	t.Cleanup(func() {
+		serverConn.Close()
+		clientConn.Close()
+	})
+
+	negotiator, err := newTelnetNegotiator(clientConn, terminal)
+	if err != nil {
+		t.Fatalf("newTelnetNegotiator(). Error: %v", err)
+	}
+
+	// offers of negotiator are sent first
+	server.expect(
+		cmdIAC, cmdWILL, optTerminalType,
+		cmdIAC, cmdWILL, optWindowSize,
+		cmdIAC, cmdWILL, optSuppressGoAhead,
+		cmdIAC, cmdDO, optSuppressGoAhead,
+		cmdIAC, cmdDO, optEcho,
+	)
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := negotiator.Read(buf)
+			if n > 0 {
+				server.data <- append([]byte{}, buf[:n]...)
+			}
+			if err != nil {
+				close(server.data)
+
+				return
+			}
+		}
+	}()
+
+	return server
+}
+
+func (o *fakeTelnetServer) send(msg ...byte) {
+	if _, err := o.conn.Write(msg); err != nil {
+		o.t.Fatalf("Send %v. Error: %v", msg, err)
+	}
+}
+
+// Next bytes received from negotiator should be msg
+func (o *fakeTelnetServer) expect(msg ...byte) {
+	o.t.Helper()
+
+	o.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	res := make([]byte, len(msg))
+	if _, err := io.ReadFull(o.conn, res); err != nil {
+		o.t.Fatalf("Read reply. Expected: %v, Error: %v", msg, err)
+	}
+
+	if !bytes.Equal(res, msg) {
+		o.t.Fatalf("Wrong reply. Expected: %v, Actual: %v", msg, res)
+	}
+}
+
+// Nothing is sent in reply of previous messages: reply of unknown option is the next one
+func (o *fakeTelnetServer) expectNoReply() {
+	o.t.Helper()
+
+	o.send(cmdIAC, cmdDO, testUnknownOption)
+	o.expect(cmdIAC, cmdWONT, testUnknownOption)
+}
+
+func TestTelnetNegotiatorWindowSize(t *testing.T) {
+	server := newFakeTelnetServer(t, TelnetTerminal{Type: "vt100", Width: 512, Height: 255})
+
+	// WILL was offered, only size is sent. 255 is escaped
+	server.send(cmdIAC, cmdDO, optWindowSize)
+	server.expect(cmdIAC, cmdSB, optWindowSize, 2, 0, 0, cmdIAC, cmdIAC, cmdIAC, cmdSE)
+
+	// option is already enabled
+	server.send(cmdIAC, cmdDO, optWindowSize)
+	server.expectNoReply()
+
+	server.send(cmdIAC, cmdDONT, optWindowSize)
+	server.expect(cmdIAC, cmdWONT, optWindowSize)
+}
+
+func TestTelnetNegotiatorTerminalType(t *testing.T) {
+	server := newFakeTelnetServer(t, TelnetTerminal{Type: "xterm", Width: 80})
+
+	server.send(cmdIAC, cmdDO, optTerminalType)
+	server.expectNoReply()
+
+	server.send(cmdIAC, cmdSB, optTerminalType, terminalTypeSend, cmdIAC, cmdSE)
+	server.expect(append(append([]byte{cmdIAC, cmdSB, optTerminalType, terminalTypeIs}, "xterm"...), cmdIAC, cmdSE)...)
+}
+
+func TestTelnetNegotiatorEcho(t *testing.T) {
+	server := newFakeTelnetServer(t, TelnetTerminal{Type: "vt100"})
+
+	// DO ECHO was requested
+	server.send(cmdIAC, cmdWILL, optEcho)
+	server.expectNoReply()
+
+	// device output is never echoed
+	server.send(cmdIAC, cmdDO, optEcho)
+	server.expect(cmdIAC, cmdWONT, optEcho)
+
+	server.send(cmdIAC, cmdWONT, optEcho)
+	server.expect(cmdIAC, cmdDONT, optEcho)
+}
+
+func TestTelnetNegotiatorSuppressGoAhead(t *testing.T) {
+	server := newFakeTelnetServer(t, TelnetTerminal{Type: "vt100"})
+
+	server.send(cmdIAC, cmdDO, optSuppressGoAhead, cmdIAC, cmdWILL, optSuppressGoAhead)
+	server.expectNoReply()
+
+	// enabled options are not negotiated again
+	server.send(cmdIAC, cmdDO, optSuppressGoAhead, cmdIAC, cmdWILL, optSuppressGoAhead)
+	server.expectNoReply()
+}
+
+func TestTelnetNegotiatorRefuseUnknown(t *testing.T) {
+	server := newFakeTelnetServer(t, TelnetTerminal{Type: "vt100"})
+
+	server.send(cmdIAC, cmdDO, testUnknownOption)
+	server.expect(cmdIAC, cmdWONT, testUnknownOption)
+
+	server.send(cmdIAC, cmdWILL, testUnknownOption)
+	server.expect(cmdIAC, cmdDONT, testUnknownOption)
+
+	// unknown subnegotiation is ignored
+	server.send(cmdIAC, cmdSB, testUnknownOption, 1, 2, cmdIAC, cmdSE)
+	server.expectNoReply()
+}
+
+func TestTelnetNegotiatorData(t *testing.T) {
+	server := newFakeTelnetServer(t, TelnetTerminal{Type: "vt100"})
+
+	// negotiation is removed, escaped IAC is kept for telnet.Conn
+	server.send('l', 'o', cmdIAC, cmdDO, optSuppressGoAhead, 'g', cmdIAC, cmdIAC, 'i', 'n', ':')
+
+	var res []byte
+	timeout := time.After(2 * time.Second)
+	for len(res) < 8 {
+		select {
+		case data, ok := <-server.data:
+			if !ok {
+				t.Fatalf("Connection closed. Data: %q", res)
+			}
+			res = append(res, data...)
+		case <-timeout:
+			t.Fatalf("Data not received. Data: %q", res)
+		}
+	}
+
+	expected := []byte{'l', 'o', 'g', cmdIAC, cmdIAC, 'i', 'n', ':'}
+	if !bytes.Equal(res, expected) {
+		t.Fatalf("Wrong data. Expected: %q, Actual: %q", expected, res)
+	}
+}
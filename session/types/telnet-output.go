@@ -0,0 +1,122 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	escapeChar    = '\x1b'
+	backspaceChar = '\b'
+)
+
+var (
+	// ESC [ params intermediates final
+	csiRegexp = regexp.MustCompile(`^\x1b\[([0-?]*)[ -/]*([@-~])`)
+	// ESC followed by single char or charset designation ESC ( B
+	escRegexp = regexp.MustCompile(`^\x1b[()#][0-9A-Za-z]|^\x1b[@-_]`)
+)
+
+// Remove echoed command, trailing prompt, pager artifacts and control sequences from telnet output.
+// Output is kept in device charset: chars are utf-8 runes if isUtf8, single bytes otherwise
+func normalizeOutput(output, command string, prompt, pager *regexp.Regexp, isUtf8 bool) string {
+	lines := strings.Split(output, "\n")
+	for idx, line := range lines {
+		line = renderLine(line, isUtf8)
+		if pager != nil {
+			line = pager.ReplaceAllString(line, "")
+		}
+		lines[idx] = strings.TrimRight(line, " \t")
+	}
+
+	// echoed command is the first line
+	if len(lines) > 0 && strings.TrimSpace(command) != "" &&
+		strings.HasSuffix(strings.TrimSpace(lines[0]), strings.TrimSpace(command)) {
+
+		lines = lines[1:]
+	}
+
+	// trailing prompt is the last line
+	if len(lines) > 0 && prompt != nil && prompt.MatchString(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Render line as terminal does: apply carriage return, backspace and
+// cursor/erase sequences and drop other control codes. Line is not decoded if it is not utf-8,
+// so bytes of other charsets are kept as is
+func renderLine(line string, isUtf8 bool) string {
+	// bytes of chars by terminal cell
+	buf := []string{}
+	cursor := 0
+
+	for i := 0; i < len(line); {
+		r, size := rune(line[i]), 1
+		if isUtf8 {
+			r, size = utf8.DecodeRuneInString(line[i:])
+		}
+
+		switch {
+		case r == '\r':
+			cursor = 0
+		case r == backspaceChar:
+			if cursor > 0 {
+				cursor--
+			}
+		case r == escapeChar:
+			if m := csiRegexp.FindStringSubmatch(line[i:]); m != nil {
+				switch m[2] {
+				// erase to end of line
+				case "K":
+					if (m[1] == "" || m[1] == "0") && cursor < len(buf) {
+						buf = buf[:cursor]
+					} else if m[1] == "2" {
+						buf = buf[:0]
+						cursor = 0
+					}
+				// cursor back
+				case "D":
+					cursor -= csiParam(m[1])
+					if cursor < 0 {
+						cursor = 0
+					}
+				}
+				size = len(m[0])
+			} else if m := escRegexp.FindString(line[i:]); m != "" {
+				size = len(m)
+			}
+		case r != '\t' && (r < ' ' || r == 0x7f):
+			// drop other control codes
+		default:
+			if cursor < len(buf) {
+				buf[cursor] = line[i : i+size]
+			} else {
+				buf = append(buf, line[i:i+size])
+			}
+			cursor++
+		}
+
+		i += size
+	}
+
+	return strings.Join(buf, "")
+}
+
+func csiParam(param string) int {
+	n := 0
+	for _, r := range param {
+		if r < '0' || r > '9' {
+			break
+		}
+		n = n*10 + int(r-'0')
+	}
+
+	if n == 0 {
+		return 1
+	}
+
+	return n
+}
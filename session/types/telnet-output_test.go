@@ -0,0 +1,50 @@
+package types
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/deminds/CmdProxy/charset"
+)
+
+var testPrompt = regexp.MustCompile(`sw1#\s*$`)
+
+func TestNormalizeOutputCharset(t *testing.T) {
+	tests := []struct {
+		name     string
+		charset  string
+		output   string
+		expected string
+	}{
+		{
+			name:     "latin1",
+			charset:  "ISO-8859-1",
+			output:   "show name\r\ncaf\xe9\r\nsw1#",
+			expected: "café",
+		},
+		{
+			name:    "koi8-r",
+			charset: "KOI8-R",
+			// erased and overwritten chars are single bytes
+			output:   "show name\r\n\xd0\xd2\xc9XX\b\b\xd7\xc5\xd4\x1b[K\r\nsw1#",
+			expected: "привет",
+		},
+		{
+			name:     "utf8",
+			charset:  "",
+			output:   "show name\r\nприве!\bт\r\nsw1#",
+			expected: "привет",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := normalizeOutput(test.output, "show name", testPrompt, nil, charset.IsUtf8(test.charset))
+
+			res, replacements := charset.ToUtf8(output, test.charset)
+			if res != test.expected || replacements != 0 {
+				t.Fatalf("Wrong output. Expected: %q, Actual: %q, Replacements: %v", test.expected, res, replacements)
+			}
+		})
+	}
+}
@@ -3,54 +3,133 @@ package types
 import (
 	"bytes"
 	"fmt"
+	"net"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/deminds/CmdProxy/charset"
 	"github.com/deminds/CmdProxy/generatorid"
 	"github.com/deminds/CmdProxy/model"
+	"github.com/deminds/CmdProxy/recording"
 	"github.com/deminds/CmdProxy/session"
 	"github.com/golang/glog"
 	"github.com/ziutek/telnet"
 )
 
 const (
-	ContinueCommand = " "
+	ContinueCommand      = " "
+	DefaultEnableCommand = "enable"
+
+	// bytes of the last line kept for prompt matching when line is longer
+	telnetMaxLineBytes = 64 * 1024
 )
 
+// Max bytes of command output kept while device output is read. The rest of output is read
+// till prompt and dropped. Set before sessions are created. 0 - unlimited
+var TelnetReadLimit int64 = 64 * 1024 * 1024
+
 func NewTelnetSession(idGenerator *generatorid.IDGenerator, timeoutSec int, requestData model.ConnectTelnetRequest) (*TelnetSession, error) {
 	id, err := idGenerator.Next()
 	if err != nil {
 		return nil, fmt.Errorf("NewTelnetSession(). Generate id. Error: %v", err)
 	}
 
+	literal := requestData.LiteralExpectedStrings()
+
+	loginRegexp, err := compileExpected(requestData.LoginExpectedString, literal)
+	if err != nil {
+		return nil, fmt.Errorf("NewTelnetSession(). Compile loginExpectedString. Error: %v", err)
+	}
+
+	passwordRegexp, err := compileExpected(requestData.PasswordExpectedString, literal)
+	if err != nil {
+		return nil, fmt.Errorf("NewTelnetSession(). Compile passwordExpectedString. Error: %v", err)
+	}
+
+	// prompt is expected at the begin of line
+	hostnameRegexp, err := compilePrompt(requestData.HostnameExpectedString, literal)
+	if err != nil {
+		return nil, fmt.Errorf("NewTelnetSession(). Compile hostnameExpectedString. Error: %v", err)
+	}
+
+	var continueRegexp *regexp.Regexp
+	if requestData.ContinueCommandExpectedString != "" {
+		continueRegexp, err = compileExpected(requestData.ContinueCommandExpectedString, literal)
+		if err != nil {
+			return nil, fmt.Errorf("NewTelnetSession(). Compile continueCommandExpectedString. Error: %v", err)
+		}
+	}
+
+	enableCommand := requestData.EnableCommand
+	if enableCommand == "" {
+		enableCommand = DefaultEnableCommand
+	}
+
+	// device usually asks enable password with the same marker as login password
+	enableRegexp := passwordRegexp
+	if requestData.EnableExpectedString != "" {
+		enableRegexp, err = compileExpected(requestData.EnableExpectedString, literal)
+		if err != nil {
+			return nil, fmt.Errorf("NewTelnetSession(). Compile enableExpectedString. Error: %v", err)
+		}
+	}
+
+	var enabledHostnameRegexp *regexp.Regexp
+	if requestData.EnabledHostnameExpectedString != "" {
+		enabledHostnameRegexp, err = compilePrompt(requestData.EnabledHostnameExpectedString, literal)
+		if err != nil {
+			return nil, fmt.Errorf("NewTelnetSession(). Compile enabledHostnameExpectedString. Error: %v", err)
+		}
+	}
+
 	sess := &TelnetSession{
 		id:          id,
 		isClose:     false,
 		sessionType: session.SessionTypeTelnet,
 		timeout:     time.Duration(timeoutSec) * time.Second,
 
-		loginExpectedString:    requestData.LoginExpectedString,
-		passwordExpectedString: requestData.PasswordExpectedString,
-		hostnameExpectedString: requestData.HostnameExpectedString,
-		continueExpectedString: requestData.ContinueCommandExpectedString,
+		loginExpected:    loginRegexp,
+		passwordExpected: passwordRegexp,
+		hostnameExpected: hostnameRegexp,
+		continueExpected: continueRegexp,
 
 		host: requestData.Host,
 		port: requestData.Port,
 
+		terminal: TelnetTerminal{
+			Type:   requestData.TerminalType,
+			Width:  requestData.TerminalWidth,
+			Height: requestData.TerminalHeight,
+		},
+		charset: requestData.Charset,
+
 		login:    requestData.Login,
 		password: requestData.Password,
 
-		output:     make(chan string),
-		command:    make(chan string),
-		disconnect: make(chan bool),
+		enablePassword:          requestData.EnablePassword,
+		enableCommand:           enableCommand,
+		enableExpected:          enableRegexp,
+		enabledHostnameExpected: enabledHostnameRegexp,
+
+		output:     make(chan session.CommandResult),
+		command:    make(chan telnetCommand),
+		disconnect: make(chan struct{}),
+		done:       make(chan struct{}),
 	}
 
-	glog.Infof("NewConsoleSession() Host: %v, Port: %v, ID: %v, Type: %v, Timeout: %v",
-		sess.host, sess.port, sess.id, sess.sessionType, sess.timeout)
+	glog.Infof("NewTelnetSession() Host: %v, Port: %v, ID: %v, Type: %v, Timeout: %v, Terminal: %+v",
+		sess.host, sess.port, sess.id, sess.sessionType, sess.timeout, sess.terminal)
 
 	return sess, nil
 }
 
+type telnetCommand struct {
+	command string
+	options session.CommandOptions
+}
+
 // TODO: remove channels
 type TelnetSession struct {
 	id          string
@@ -58,36 +137,101 @@ type TelnetSession struct {
 	sessionType session.SessionType
 	timeout     time.Duration
 
-	loginExpectedString    string
-	passwordExpectedString string
-	hostnameExpectedString string
-	continueExpectedString string
+	loginExpected    *regexp.Regexp
+	passwordExpected *regexp.Regexp
+	hostnameExpected *regexp.Regexp
+	continueExpected *regexp.Regexp
+
+	// last matched prompt and CLI mode inferred from it
+	prompt string
+	mode   session.CliMode
 
 	host string
 	port int
 
+	terminal TelnetTerminal
+	// charset of device output
+	charset string
+
 	login    string
 	password string
 
+	enablePassword          string
+	enableCommand           string
+	enableExpected          *regexp.Regexp
+	enabledHostnameExpected *regexp.Regexp
+
 	sess *telnet.Conn
+	// TCP connection of sess, closed once when session ends
+	conn      net.Conn
+	connClose sync.Once
+	// nil if session is not recorded
+	recorder *recording.Recorder
+	// nil if events are not sent
+	notifier session.INotifier
+
+	// output bytes kept for running command and whether the rest is dropped
+	readBytes     int64
+	readTruncated bool
+	// output lines of running command are passed to it if it is set
+	outputChunk func([]byte)
+
+	command    chan telnetCommand
+	output     chan session.CommandResult
+	disconnect chan struct{}
+	closeOnce  sync.Once
+	// closed when start() exits
+	done      chan struct{}
+	connected bool
+}
+
+// Record session transcript. Should be called before Connect()
+func (o *TelnetSession) SetRecorder(recorder *recording.Recorder) {
+	o.recorder = recorder
+}
 
-	command    chan string
-	output     chan string
-	disconnect chan bool
+// Send session events to notifier. Should be called before Connect()
+func (o *TelnetSession) SetNotifier(notifier session.INotifier) {
+	o.notifier = notifier
 }
 
-func (o *TelnetSession) Connect() error {
+func (o *TelnetSession) Connect() (err error) {
 	logPrefix := "TelnetSession.Connect()"
 
+	defer func() {
+		if err != nil {
+			o.isClose = true
+			o.closeConn()
+			o.closeRecorder()
+		}
+	}()
+
 	addr := fmt.Sprintf("%v:%v", o.host, o.port)
 
 	glog.Infof("%v Addr: %v, ID: %v, Type: %v", logPrefix, addr, o.id, o.sessionType)
 
-	sess, err := telnet.Dial("tcp", addr)
+	conn, err := net.DialTimeout("tcp", addr, o.timeout)
 	if err != nil {
-		o.isClose = true
+		return fmt.Errorf("%v Error net.DialTimeout(). ID: %v, Type: %v, Addr: %v, Error: %v",
+			logPrefix, o.id, o.sessionType, addr, err)
+	}
 
-		return fmt.Errorf("%v Error telnet.Dial(). ID: %v, Type: %v, Addr: %v, Error: %v",
+	o.conn = conn
+
+	negotiator, err := newTelnetNegotiator(conn, o.terminal)
+	if err != nil {
+		return fmt.Errorf("%v Error newTelnetNegotiator(). ID: %v, Type: %v, Addr: %v, Error: %v",
+			logPrefix, o.id, o.sessionType, addr, err)
+	}
+
+	var deviceConn net.Conn = negotiator
+	if o.recorder != nil {
+		deviceConn = recording.NewConn(negotiator, o.recorder)
+	}
+
+	sess, err := telnet.NewConn(deviceConn)
+	if err != nil {
+		return fmt.Errorf("%v Error telnet.NewConn(). ID: %v, Type: %v, Addr: %v, Error: %v",
 			logPrefix, o.id, o.sessionType, addr, err)
 	}
 
@@ -95,9 +239,9 @@ func (o *TelnetSession) Connect() error {
 	o.sess = sess
 
 	// login
-	resp, err := o.readUntil(o.loginExpectedString)
+	resp, _, _, err := o.readUntil(o.loginExpected)
 	if err != nil {
-		return fmt.Errorf("%v Read after connect. Wait: %v, Error: %v", logPrefix, o.loginExpectedString, err)
+		return fmt.Errorf("%v Read after connect. Wait: %v, Error: %v", logPrefix, o.loginExpected, err)
 	}
 	glog.Infof("%v Connect. Response: %v", logPrefix, resp)
 
@@ -105,56 +249,151 @@ func (o *TelnetSession) Connect() error {
 		return fmt.Errorf("%v Send login. Error: %v", logPrefix, err)
 	}
 
-	resp, err = o.readUntil(o.passwordExpectedString)
+	resp, _, _, err = o.readUntil(o.passwordExpected)
 	if err != nil {
 		return fmt.Errorf("%v Read after send login. Error: %v", logPrefix, err)
 	}
 	glog.Infof("%v Send login. Response: %v", logPrefix, resp)
 
+	o.maskNextInput()
 	if err := o.sendLine(o.password); err != nil {
 		return fmt.Errorf("%v Send password. Error: %v", logPrefix, err)
 	}
 
-	resp, err = o.readUntil(o.hostnameExpectedString)
+	resp, _, prompt, err := o.readUntil(o.hostnameExpected)
 	if err != nil {
-		return fmt.Errorf("%v Read after send password. Wait: %v, Error: %v", logPrefix, o.hostnameExpectedString, err)
+		return fmt.Errorf("%v Read after send password. Wait: %v, Error: %v", logPrefix, o.hostnameExpected, err)
+	}
+	o.setPrompt(prompt)
+	glog.Infof("%v Send password. Prompt: '%v', Mode: %v, Response: %v", logPrefix, o.prompt, o.mode, resp)
+
+	if o.enablePassword != "" {
+		if err := o.enable(); err != nil {
+			return fmt.Errorf("%v Escalate to privileged mode. Error: %v", logPrefix, err)
+		}
 	}
-	glog.Infof("%v Send password. Response: %v", logPrefix, resp)
 
+	o.notify(model.EventSessionConnected, "", nil)
+
+	o.connected = true
 	go o.start()
 
 	return nil
 }
 
-func (o *TelnetSession) Command(command string) (string, error) {
+// Escalate to privileged mode and verify new prompt
+func (o *TelnetSession) enable() error {
+	logPrefix := "TelnetSession.enable()"
+
+	if err := o.sendLine(o.enableCommand); err != nil {
+		return fmt.Errorf("%v Send enable command. Command: '%v', Error: %v", logPrefix, o.enableCommand, err)
+	}
+
+	resp, idx, prompt, err := o.readUntil(o.enableExpected, o.hostnameExpected)
+	if err != nil {
+		return fmt.Errorf("%v Read after send enable command. Wait: %v, Error: %v", logPrefix, o.enableExpected, err)
+	}
+	glog.Infof("%v Send enable command. Response: %v", logPrefix, resp)
+
+	// device may skip password and return prompt at once
+	if idx == 0 {
+		o.maskNextInput()
+		if err := o.sendLine(o.enablePassword); err != nil {
+			return fmt.Errorf("%v Send enable password. Error: %v", logPrefix, err)
+		}
+
+		resp, _, prompt, err = o.readUntil(o.hostnameExpected)
+		if err != nil {
+			return fmt.Errorf("%v Read after send enable password. Wait: %v, Error: %v", logPrefix, o.hostnameExpected, err)
+		}
+		glog.Infof("%v Send enable password. Response: %v", logPrefix, resp)
+	}
+
+	o.setPrompt(prompt)
+
+	if o.enabledHostnameExpected != nil {
+		if !o.enabledHostnameExpected.MatchString(o.prompt) {
+			return fmt.Errorf("%v Prompt does not match after escalation. "+
+				"ID: %v, Prompt: '%v', Expected: %v", logPrefix, o.id, o.prompt, o.enabledHostnameExpected)
+		}
+	} else if o.mode != session.CliModePrivileged {
+		return fmt.Errorf("%v Prompt is not privileged after escalation. "+
+			"ID: %v, Prompt: '%v', Mode: %v", logPrefix, o.id, o.prompt, o.mode)
+	}
+
+	glog.Infof("%v Escalated. ID: %v, Prompt: '%v', Mode: %v", logPrefix, o.id, o.prompt, o.mode)
+
+	return nil
+}
+
+func (o *TelnetSession) Command(command string, options session.CommandOptions) (session.CommandResult, error) {
 	logPrefix := "TelnetSession.Command()"
 
 	glog.Infof("%v Execute command. "+
 		"ID: %v, Type: %v, Command: '%v'", logPrefix, o.id, o.sessionType, command)
 
 	if o.isClose {
-		return "", fmt.Errorf("%v Session is close. "+
+		err := fmt.Errorf("%v Session is close. "+
 			"ID: %v, Type: %v, Command: %v", logPrefix, o.id, o.sessionType, command)
+		o.notify(model.EventCommandFailed, command, err)
+
+		return session.CommandResult{}, err
 	}
 
-	o.command <- command
+	if options.Stdin != nil || options.KeepStdinOpen {
+		return session.CommandResult{}, fmt.Errorf("%v Stdin is not supported. "+
+			"ID: %v, Type: %v, Command: %v", logPrefix, o.id, o.sessionType, command)
+	}
+
+	select {
+	case o.command <- telnetCommand{command: command, options: options}:
+	case <-o.done:
+		return session.CommandResult{}, fmt.Errorf("%v Session is close. "+
+			"ID: %v, Type: %v, Command: %v", logPrefix, o.id, o.sessionType, command)
+	}
 
 	select {
 	case res := <-o.output:
 		glog.Infof("%v Received output. "+
-			"ID: %v, Type: %v, Output: '%s'", logPrefix, o.id, o.sessionType, res)
+			"ID: %v, Type: %v, Prompt: '%v', Mode: %v, Output: '%s'",
+			logPrefix, o.id, o.sessionType, res.Prompt, res.Mode, res.Output)
 
 		return res, nil
-	case <-time.After(o.timeout):
+	case <-o.done:
+		err := fmt.Errorf("%v Session was closed while command is executed. "+
+			"ID: %v, Type: %v", logPrefix, o.id, o.sessionType)
+		o.notify(model.EventCommandFailed, command, err)
+
+		return session.CommandResult{}, err
+	case <-time.After(o.commandTimeout(options)):
 		o.isClose = true
+		// unblock reading of start()
+		o.closeConn()
 
-		return "", fmt.Errorf("%v Timeout wait output. "+
+		err := fmt.Errorf("%v Timeout wait output. "+
 			"ID: %v, Type: %v", logPrefix, o.id, o.sessionType)
+		o.notify(model.EventCommandFailed, command, err)
+
+		return session.CommandResult{}, err
 	}
 }
 
+// Time to wait output of command with all dialog steps
+func (o *TelnetSession) commandTimeout(options session.CommandOptions) time.Duration {
+	timeout := o.timeout
+	for _, step := range options.Expect {
+		if step.Timeout > 0 {
+			timeout += step.Timeout
+		} else {
+			timeout += o.timeout
+		}
+	}
+
+	return timeout
+}
+
 func (o *TelnetSession) Ping() bool {
-	if _, err := o.Command(PingCommand); err != nil {
+	if _, err := o.Command(PingCommand, session.CommandOptions{}); err != nil {
 		return false
 	}
 
@@ -177,124 +416,288 @@ func (o *TelnetSession) Close() {
 	glog.Infof("TelnetSession.Close(). ID: %v, Type: %v", o.id, o.sessionType)
 
 	o.isClose = true
-	o.disconnect <- true
+	o.closeOnce.Do(func() {
+		close(o.disconnect)
+	})
+	// running command is aborted, device line is released at once
+	o.closeConn()
+
+	if o.connected {
+		<-o.done
+	}
 }
 
 func (o *TelnetSession) start() {
 	logPrefix := "TelnetSession.start()"
+	defer close(o.done)
+	defer o.closeRecorder()
+	defer o.closeConn()
+
 	for {
 		select {
-		case cmd, ok := <-o.command:
+		case c, ok := <-o.command:
 			if !ok {
 				glog.Infof("%v Command chan was close. Exit routine. "+
 					"ID: %v, Type: %v", logPrefix, o.id, o.sessionType)
 				o.isClose = true
+				o.notify(model.EventSessionClosed, "", nil)
 
 				return
 			}
 
-			cmd = strings.Trim(cmd, " ")
+			cmd := strings.Trim(c.command, " ")
 			if cmd == "" {
-				o.output <- EmptyCommandMsg
+				o.output <- session.CommandResult{Output: EmptyCommandMsg, Prompt: o.prompt, Mode: o.mode, Charset: o.charset}
 
 				continue
 			}
 
-			if err := o.sendLine(cmd); err != nil {
-				glog.Errorf("%v Send command. Exit routine. Command: %v, Error: %v", logPrefix, cmd, err)
+			o.outputChunk = c.options.OutputChunk
+			resp, prompt, err := o.execute(cmd, c.options.Expect)
+			o.outputChunk = nil
+			if err != nil {
+				glog.Errorf("%v Execute command. Exit routine. Command: %v, Error: %v", logPrefix, cmd, err)
 				o.isClose = true
+				o.notify(model.EventSessionClosed, cmd, err)
 
 				return
 			}
+			o.setPrompt(prompt)
 
-			resp, err := o.readStringUntil(o.hostnameExpectedString)
-			if err != nil {
-				glog.Errorf("%v Read after send command. Exit routine. Wait: %v, Error: %v", logPrefix, o.hostnameExpectedString, err)
-				o.isClose = true
-
-				return
+			if !c.options.Raw {
+				resp = normalizeOutput(resp, cmd, o.hostnameExpected, o.continueExpected, charset.IsUtf8(o.charset))
+			}
+			if o.readTruncated {
+				glog.Errorf("%v Output limit is reached. ID: %v, Command: %v, Limit: %v", logPrefix, o.id, cmd, TelnetReadLimit)
 			}
 
 			if !o.isClose {
-				o.output <- resp
+				select {
+				case o.output <- session.CommandResult{Output: resp, Prompt: o.prompt, Mode: o.mode, Charset: o.charset, Truncated: o.readTruncated}:
+				case <-o.disconnect:
+				}
 			} else {
-				glog.Info("%v Session was closed. Exit routine. Id: %v, Type: %v", logPrefix, o.id, o.sessionType)
+				glog.Infof("%v Session was closed. Exit routine. Id: %v, Type: %v", logPrefix, o.id, o.sessionType)
 				o.isClose = true
+				o.notify(model.EventSessionClosed, "", nil)
 
 				return
 			}
 
-		case d := <-o.disconnect:
-			if d {
-				glog.Infof("%v Received disconnect request. "+
-					"ID: %v, Type: %v", logPrefix, o.id, o.sessionType)
-				o.isClose = true
+		case <-o.disconnect:
+			glog.Infof("%v Received disconnect request. "+
+				"ID: %v, Type: %v", logPrefix, o.id, o.sessionType)
+			o.isClose = true
+			o.notify(model.EventSessionClosed, "", nil)
 
-				return
-			}
+			return
 
 		case <-time.After(o.timeout):
 			glog.Infof("%v Timeout between commands was reach. Drop session. "+
 				"ID: %v, Type: %v", logPrefix, o.id, o.sessionType)
 			o.isClose = true
+			o.notify(model.EventSessionTimeout, "", nil)
 
 			return
 		}
 	}
 }
 
-// Will find delim in full output
-func (o *TelnetSession) readUntil(delim string) (string, error) {
-	logPrefix := "TelnetSession.readUntil()"
+// Literal expected string is matched as text
+func compileExpected(expected string, literal bool) (*regexp.Regexp, error) {
+	if literal {
+		expected = regexp.QuoteMeta(expected)
+	}
 
-	o.sess.SetReadDeadline(time.Now().Add(o.timeout))
-	resBytes, err := o.sess.ReadUntil(delim)
-	if err != nil {
-		return "", fmt.Errorf("%v o.sess.ReadUntil() "+
-			"ID: %v, Delim: %v, Error: %v", logPrefix, o.id, delim, err)
+	return regexp.Compile(expected)
+}
+
+// Prompt is expected at the begin of line
+func compilePrompt(expected string, literal bool) (*regexp.Regexp, error) {
+	if literal {
+		expected = regexp.QuoteMeta(expected)
 	}
 
-	return string(resBytes), nil
+	return regexp.Compile("^(?:" + expected + ")")
 }
 
-// Will find delim in begin of string
-func (o *TelnetSession) readStringUntil(delim string) (string, error) {
-	logPrefix := "TelnetSession.readStringUntil()"
+// Will read until one of regexps match the last line of output.
+// Return output, index of matched regexp and matched string
+func (o *TelnetSession) readUntil(res ...*regexp.Regexp) (string, int, string, error) {
+	return o.readUntilTimeout(o.timeout, res...)
+}
+
+func (o *TelnetSession) readUntilTimeout(timeout time.Duration, res ...*regexp.Regexp) (string, int, string, error) {
+	logPrefix := "TelnetSession.readUntilTimeout()"
+
+	buf := bytes.Buffer{}
+	// last line is kept apart from output, so prompt is found after output limit.
+	// Output does not end with prompt then
+	line := []byte{}
+	// start of output which is not passed to outputChunk yet
+	chunkStart := 0
 
-	// append next line '\n' before delimiter
-	delim = string([]byte{10}) + delim
+	o.sess.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		b, err := o.sess.ReadByte()
+		if err != nil {
+			return "", -1, "", fmt.Errorf("%v o.sess.ReadByte() "+
+				"ID: %v, Delims: %v, Error: %v", logPrefix, o.id, res, err)
+		}
+
+		if TelnetReadLimit <= 0 || o.readBytes < TelnetReadLimit {
+			buf.WriteByte(b)
+			o.readBytes++
+		} else {
+			o.readTruncated = true
+		}
 
-	delims := []string{}
-	if o.continueExpectedString != "" {
-		delims = append(delims, o.continueExpectedString)
+		// device may redraw line after carriage return
+		if b == '\n' || b == '\r' {
+			line = line[:0]
+
+			if b == '\n' && o.outputChunk != nil && buf.Len() > chunkStart {
+				o.outputChunk(buf.Bytes()[chunkStart:])
+				chunkStart = buf.Len()
+			}
+
+			continue
+		}
+
+		line = append(line, b)
+		if len(line) > telnetMaxLineBytes {
+			line = append(line[:0], line[len(line)-telnetMaxLineBytes/2:]...)
+		}
+
+		for idx, re := range res {
+			if match := re.Find(line); match != nil {
+				return buf.String(), idx, string(match), nil
+			}
+		}
+	}
+}
+
+// Will read until one of regexps match and press continue on pager marker.
+// Return output, index of matched regexp and matched string
+func (o *TelnetSession) readStringUntil(timeout time.Duration, res ...*regexp.Regexp) (string, int, string, error) {
+	logPrefix := "TelnetSession.readStringUntil()"
+
+	delims := res
+	if o.continueExpected != nil {
+		delims = append(append([]*regexp.Regexp{}, res...), o.continueExpected)
 	}
-	delims = append(delims, delim)
 
 	buf := bytes.Buffer{}
 	for {
-		o.sess.SetReadDeadline(time.Now().Add(o.timeout))
-		resBytes, idx, err := o.sess.ReadUntilIndex(delims...)
+		resp, idx, match, err := o.readUntilTimeout(timeout, delims...)
 		if err != nil {
-			return "", fmt.Errorf("%v o.sess.ReadUntilIndex() "+
-				"ID: %v, Delim: %v, Error: %v", logPrefix, o.id, delim, err)
+			return "", -1, "", fmt.Errorf("%v o.readUntil() "+
+				"ID: %v, Delims: %v, Error: %v", logPrefix, o.id, res, err)
 		}
 
-		if _, err := buf.Write(resBytes); err != nil {
-			return "", fmt.Errorf("%v buf.Write() "+
-				"ID: %v, Delim: %v, Error: %v", logPrefix, o.id, delim, err)
+		buf.WriteString(resp)
+
+		if idx < len(res) {
+			return buf.String(), idx, match, nil
 		}
 
-		if o.continueExpectedString == "" || idx != 0 {
-			break
+		if err := o.sendLine(ContinueCommand); err != nil {
+			return "", -1, "", fmt.Errorf("%v sendLine() "+
+				"ID: %v, Delims: %v, Error: %v", logPrefix, o.id, res, err)
 		}
+	}
+}
+
+// Send command, answer expected questions and read output until prompt.
+// Return whole transcript and matched prompt
+func (o *TelnetSession) execute(cmd string, steps []session.ExpectStep) (string, string, error) {
+	logPrefix := "TelnetSession.execute()"
+
+	o.readBytes = 0
+	o.readTruncated = false
+
+	if err := o.sendLine(cmd); err != nil {
+		return "", "", fmt.Errorf("%v Send command. Command: %v, Error: %v", logPrefix, cmd, err)
+	}
 
-		if err := o.sendLine(" "); err != nil {
-			return "", fmt.Errorf("%v sendLine() "+
-				"ID: %v, Delim: %v, Error: %v", logPrefix, o.id, delim, err)
+	buf := bytes.Buffer{}
+	for idx, step := range steps {
+		timeout := o.timeout
+		if step.Timeout > 0 {
+			timeout = step.Timeout
+		}
+
+		resp, matchIdx, prompt, err := o.readStringUntil(timeout, o.hostnameExpected, step.Expect)
+		if err != nil {
+			return "", "", fmt.Errorf("%v Read step %v. Wait: %v, Error: %v", logPrefix, idx, step.Expect, err)
+		}
+		buf.WriteString(resp)
+
+		// device returned prompt before all questions were asked
+		if matchIdx == 0 {
+			glog.Infof("%v Prompt reached on step %v. Skip other steps. ID: %v, Command: %v", logPrefix, idx, o.id, cmd)
+
+			return buf.String(), prompt, nil
 		}
+
+		if err := o.sendLine(step.Send); err != nil {
+			return "", "", fmt.Errorf("%v Send step %v. Error: %v", logPrefix, idx, err)
+		}
+	}
+
+	resp, _, prompt, err := o.readStringUntil(o.timeout, o.hostnameExpected)
+	if err != nil {
+		return "", "", fmt.Errorf("%v Read after send command. Wait: %v, Error: %v", logPrefix, o.hostnameExpected, err)
+	}
+	buf.WriteString(resp)
+
+	return buf.String(), prompt, nil
+}
+
+func (o *TelnetSession) setPrompt(prompt string) {
+	o.prompt = strings.TrimSpace(prompt)
+	o.mode = session.DetectCliMode(o.prompt)
+}
+
+// Hide next sent line in recording
+func (o *TelnetSession) maskNextInput() {
+	if o.recorder != nil {
+		o.recorder.MaskNextInput()
+	}
+}
+
+func (o *TelnetSession) notify(eventType model.EventType, command string, err error) {
+	if o.notifier == nil {
+		return
 	}
 
-	return buf.String(), nil
+	event := model.WebhookEvent{
+		Type:        eventType,
+		SessionId:   o.id,
+		SessionType: string(o.sessionType),
+		Host:        o.host,
+		Port:        o.port,
+		Command:     command,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	o.notifier.Notify(event)
+}
+
+func (o *TelnetSession) closeConn() {
+	o.connClose.Do(func() {
+		if o.conn != nil {
+			o.conn.Close()
+		}
+	})
+}
+
+func (o *TelnetSession) closeRecorder() {
+	if o.recorder != nil {
+		o.recorder.Close()
+	}
 }
 
 func (o *TelnetSession) sendLine(cmd string) error {
@@ -0,0 +1,294 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/deminds/CmdProxy/generatorid"
+	"github.com/deminds/CmdProxy/model"
+	"github.com/golang/glog"
+)
+
+const (
+	EventHeader     = "X-CmdProxy-Event"
+	DeliveryHeader  = "X-CmdProxy-Delivery"
+	SignatureHeader = "X-CmdProxy-Signature"
+	SignaturePrefix = "sha256="
+
+	deliveryTimeout = 10 * time.Second
+	queueSize       = 1000
+)
+
+func NewDispatcher(
+	idGenerator *generatorid.IDGenerator,
+	workers int,
+	maxAttempts int,
+	backoff time.Duration,
+	deadLetterPath string) *Dispatcher {
+
+	if workers < 1 {
+		workers = 1
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &Dispatcher{
+		idGenerator:    idGenerator,
+		workers:        workers,
+		maxAttempts:    maxAttempts,
+		backoff:        backoff,
+		deadLetterPath: deadLetterPath,
+
+		hooks:      map[string]model.Webhook{},
+		queue:      make(chan delivery, queueSize),
+		httpClient: &http.Client{Timeout: deliveryTimeout},
+	}
+}
+
+// Deliver events to registered webhooks. Failed delivery is retried with exponential backoff
+// and written to dead letter file after the last attempt
+type Dispatcher struct {
+	idGenerator *generatorid.IDGenerator
+	workers     int
+	maxAttempts int
+	// delay before the second attempt, doubled for every next one
+	backoff time.Duration
+	// events are only logged if empty
+	deadLetterPath string
+
+	hooks map[string]model.Webhook
+	mutex sync.RWMutex
+
+	queue           chan delivery
+	deadLetterMutex sync.Mutex
+	httpClient      *http.Client
+}
+
+type delivery struct {
+	hook  model.Webhook
+	event model.WebhookEvent
+}
+
+type deadLetter struct {
+	Url      string             `json:"url"`
+	Attempts int                `json:"attempts"`
+	Error    string             `json:"error"`
+	Event    model.WebhookEvent `json:"event"`
+}
+
+func (o *Dispatcher) Add(hook model.Webhook) (model.Webhook, error) {
+	id, err := o.idGenerator.Next()
+	if err != nil {
+		return hook, fmt.Errorf("Dispatcher.Add() Generate id. Error: %v", err)
+	}
+	hook.Id = id
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.hooks[id] = hook
+	glog.Infof("Dispatcher.Add() ID: %v, Url: %v, Events: %v", hook.Id, hook.Url, hook.Events)
+
+	return hook, nil
+}
+
+// Webhooks sorted by id. Secrets are hidden
+func (o *Dispatcher) List() []model.Webhook {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	res := make([]model.Webhook, 0, len(o.hooks))
+	for _, hook := range o.hooks {
+		hook.Secret = ""
+		res = append(res, hook)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+
+	return res
+}
+
+func (o *Dispatcher) Delete(id string) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.hooks[id]; !ok {
+		return fmt.Errorf("Dispatcher.Delete() Webhook not found. ID: %v", id)
+	}
+
+	delete(o.hooks, id)
+	glog.Infof("Dispatcher.Delete() ID: %v", id)
+
+	return nil
+}
+
+// Send event to subscribed webhooks. Does not block
+func (o *Dispatcher) Notify(event model.WebhookEvent) {
+	event = o.prepare(event)
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	for _, hook := range o.hooks {
+		if hook.IsSubscribed(event.Type) {
+			o.enqueue(hook, event)
+		}
+	}
+}
+
+// Send event to one webhook which is not registered. Does not block
+func (o *Dispatcher) Deliver(hook model.Webhook, event model.WebhookEvent) {
+	o.enqueue(hook, o.prepare(event))
+}
+
+func (o *Dispatcher) Start() {
+	glog.Infof("Dispatcher.Start() Workers: %v, MaxAttempts: %v, Backoff: %v, DeadLetter: %v",
+		o.workers, o.maxAttempts, o.backoff, o.deadLetterPath)
+
+	for i := 0; i < o.workers; i++ {
+		go func() {
+			for d := range o.queue {
+				o.deliver(d)
+			}
+		}()
+	}
+}
+
+func (o *Dispatcher) prepare(event model.WebhookEvent) model.WebhookEvent {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	if event.Id == "" {
+		id, err := o.idGenerator.Next()
+		if err != nil {
+			glog.Errorf("Dispatcher.prepare() Generate id. Error: %v", err)
+		}
+		event.Id = id
+	}
+
+	return event
+}
+
+func (o *Dispatcher) enqueue(hook model.Webhook, event model.WebhookEvent) {
+	select {
+	case o.queue <- delivery{hook: hook, event: event}:
+	default:
+		o.writeDeadLetter(hook, event, 0, fmt.Errorf("delivery queue is full"))
+	}
+}
+
+func (o *Dispatcher) deliver(d delivery) {
+	logPrefix := "Dispatcher.deliver()"
+
+	body, err := json.Marshal(d.event)
+	if err != nil {
+		glog.Errorf("%v Marshal event. ID: %v, Error: %v", logPrefix, d.event.Id, err)
+
+		return
+	}
+
+	backoff := o.backoff
+	for attempt := 1; ; attempt++ {
+		err = o.post(d.hook, d.event, body)
+		if err == nil {
+			glog.Infof("%v Delivered. ID: %v, Type: %v, Url: %v, Attempt: %v", logPrefix, d.event.Id, d.event.Type, d.hook.Url, attempt)
+
+			return
+		}
+
+		glog.Errorf("%v ID: %v, Url: %v, Attempt: %v, Error: %v", logPrefix, d.event.Id, d.hook.Url, attempt, err)
+
+		if attempt >= o.maxAttempts {
+			o.writeDeadLetter(d.hook, d.event, attempt, err)
+
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (o *Dispatcher) post(hook model.Webhook, event model.WebhookEvent, body []byte) error {
+	request, err := http.NewRequest(http.MethodPost, hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, string(event.Type))
+	request.Header.Set(DeliveryHeader, event.Id)
+	if hook.Secret != "" {
+		request.Header.Set(SignatureHeader, Sign(hook.Secret, body))
+	}
+
+	resp, err := o.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status: %v", resp.Status)
+	}
+
+	return nil
+}
+
+// Append failed delivery to dead letter file as json line
+func (o *Dispatcher) writeDeadLetter(hook model.Webhook, event model.WebhookEvent, attempts int, deliveryErr error) {
+	logPrefix := "Dispatcher.writeDeadLetter()"
+	glog.Errorf("%v Event is not delivered. ID: %v, Type: %v, Url: %v, Error: %v", logPrefix, event.Id, event.Type, hook.Url, deliveryErr)
+
+	if o.deadLetterPath == "" {
+		return
+	}
+
+	line, err := json.Marshal(deadLetter{
+		Url:      hook.Url,
+		Attempts: attempts,
+		Error:    deliveryErr.Error(),
+		Event:    event,
+	})
+	if err != nil {
+		glog.Errorf("%v Marshal. ID: %v, Error: %v", logPrefix, event.Id, err)
+
+		return
+	}
+
+	o.deadLetterMutex.Lock()
+	defer o.deadLetterMutex.Unlock()
+
+	file, err := os.OpenFile(o.deadLetterPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		glog.Errorf("%v Open. Path: %v, Error: %v", logPrefix, o.deadLetterPath, err)
+
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		glog.Errorf("%v Write. Path: %v, Error: %v", logPrefix, o.deadLetterPath, err)
+	}
+}
+
+// Hex HMAC-SHA256 of body with "sha256=" prefix
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
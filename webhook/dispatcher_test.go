@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deminds/CmdProxy/model"
+)
+
+const testBackoff = 50 * time.Millisecond
+
+// Received delivery of test receiver
+type testDelivery struct {
+	time   time.Time
+	header http.Header
+	body   []byte
+}
+
+// Receiver answers status to every delivery and keeps them
+type testReceiver struct {
+	server *httptest.Server
+	status int
+
+	deliveries chan testDelivery
+}
+
+func newTestReceiver(t *testing.T, status int) *testReceiver {
+	receiver := &testReceiver{
+		status:     status,
+		deliveries: make(chan testDelivery, 100),
+	}
+
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(respWriter http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		receiver.deliveries <- testDelivery{time: time.Now(), header: request.Header, body: body}
+
+		respWriter.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.server.Close)
+
+	return receiver
+}
+
+func (o *testReceiver) next(t *testing.T) testDelivery {
+	t.Helper()
+
+	select {
+	case d := <-o.deliveries:
+		return d
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Delivery is not received")
+	}
+
+	return testDelivery{}
+}
+
+func (o *testReceiver) expectNoMore(t *testing.T) {
+	t.Helper()
+
+	select {
+	case d := <-o.deliveries:
+		t.Fatalf("Unexpected delivery: %s", d.body)
+	case <-time.After(4 * testBackoff):
+	}
+}
+
+// Event id is set, so id generator is not used
+func testEvent() model.WebhookEvent {
+	return model.WebhookEvent{
+		Id:        "42",
+		Type:      model.EventSessionClosed,
+		Time:      time.Now(),
+		SessionId: "s1",
+	}
+}
+
+func TestDispatcherSignature(t *testing.T) {
+	receiver := newTestReceiver(t, http.StatusOK)
+
+	dispatcher := NewDispatcher(nil, 1, 3, testBackoff, "")
+	dispatcher.Start()
+	dispatcher.Deliver(model.Webhook{Url: receiver.server.URL, Secret: "s3cret"}, testEvent())
+
+	d := receiver.next(t)
+
+	signature := d.header.Get(SignatureHeader)
+	if !strings.HasPrefix(signature, SignaturePrefix) {
+		t.Fatalf("Signature has no %v prefix: %v", SignaturePrefix, signature)
+	}
+	if signature != Sign("s3cret", d.body) {
+		t.Fatalf("Wrong signature. Expected: %v, Actual: %v", Sign("s3cret", d.body), signature)
+	}
+	if d.header.Get(EventHeader) != string(model.EventSessionClosed) || d.header.Get(DeliveryHeader) != "42" {
+		t.Fatalf("Wrong event headers: %v", d.header)
+	}
+
+	event := model.WebhookEvent{}
+	if err := json.Unmarshal(d.body, &event); err != nil || event.SessionId != "s1" {
+		t.Fatalf("Wrong body: %s, Error: %v", d.body, err)
+	}
+
+	// delivered at the first attempt
+	receiver.expectNoMore(t)
+}
+
+func TestDispatcherNoSignatureWithoutSecret(t *testing.T) {
+	receiver := newTestReceiver(t, http.StatusOK)
+
+	dispatcher := NewDispatcher(nil, 1, 1, testBackoff, "")
+	dispatcher.Start()
+	dispatcher.Deliver(model.Webhook{Url: receiver.server.URL}, testEvent())
+
+	if d := receiver.next(t); d.header.Get(SignatureHeader) != "" {
+		t.Fatalf("Signature is set without secret: %v", d.header.Get(SignatureHeader))
+	}
+}
+
+func TestDispatcherRetryAndDeadLetter(t *testing.T) {
+	receiver := newTestReceiver(t, http.StatusServiceUnavailable)
+	deadLetterPath := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+
+	dispatcher := NewDispatcher(nil, 1, 3, testBackoff, deadLetterPath)
+	dispatcher.Start()
+	dispatcher.Deliver(model.Webhook{Url: receiver.server.URL, Secret: "s3cret"}, testEvent())
+
+	attempts := []testDelivery{receiver.next(t), receiver.next(t), receiver.next(t)}
+	receiver.expectNoMore(t)
+
+	// backoff is doubled for every next attempt
+	for i, expected := range []time.Duration{testBackoff, 2 * testBackoff} {
+		if delay := attempts[i+1].time.Sub(attempts[i].time); delay < expected {
+			t.Fatalf("Attempt %v is too early. Expected delay: %v, Actual: %v", i+2, expected, delay)
+		}
+	}
+
+	line := readDeadLetter(t, deadLetterPath)
+	if line.Attempts != 3 || line.Url != receiver.server.URL || line.Event.Id != "42" {
+		t.Fatalf("Wrong dead letter: %+v", line)
+	}
+	if !strings.Contains(line.Error, "503") {
+		t.Fatalf("Dead letter has no status. Error: %v", line.Error)
+	}
+}
+
+// Dead letter is written by worker after the last attempt, so file is waited for
+func readDeadLetter(t *testing.T, path string) deadLetter {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		data, err := os.ReadFile(path)
+		if err == nil && strings.HasSuffix(string(data), "\n") {
+			lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			if len(lines) != 1 {
+				t.Fatalf("Wrong number of dead letter lines: %q", lines)
+			}
+
+			res := deadLetter{}
+			if err := json.Unmarshal([]byte(lines[0]), &res); err != nil {
+				t.Fatalf("Unmarshal dead letter. Line: %v, Error: %v", lines[0], err)
+			}
+
+			return res
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("Dead letter is not written. Error: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}